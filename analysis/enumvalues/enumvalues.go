@@ -0,0 +1,313 @@
+// Package enumvalues detects enums produced by go-safe-enum-generator and
+// exports their set of slugs as facts, so that other analyzers can validate
+// uses of those enums across package boundaries.
+package enumvalues
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer computes the set of generated enums visible to a package.
+var Analyzer = &analysis.Analyzer{
+	Name:       "enumvalues",
+	Doc:        "collect the values of enums generated by go-safe-enum-generator",
+	Run:        run,
+	FactTypes:  []analysis.Fact{new(ValuesFact)},
+	ResultType: reflect.TypeOf(Result{}),
+}
+
+// ValuesFact records the slugs of a generated enum type, in declaration order.
+type ValuesFact struct {
+	Name  string
+	Slugs []string
+	// Members maps each slug to the name of the package-level variable holding it.
+	Members map[string]string
+	// Keys lists the lower case strings accepted by Parse, as found in the
+	// generated lookup table: the slugs and, with the protojson option, the
+	// protobuf JSON names. It's empty if the table wasn't found.
+	Keys []string
+	// Int is set for the int style, the ints of the members being the
+	// indexes of their slugs.
+	Int bool
+}
+
+// AFact implements the analysis.Fact interface.
+func (*ValuesFact) AFact() {}
+
+func (f *ValuesFact) String() string {
+	return "enum(" + strings.Join(f.Slugs, ", ") + ")"
+}
+
+// Result maps the type name of each generated enum (declared in the current
+// package or in one of its dependencies) to its values.
+type Result map[*types.TypeName]*ValuesFact
+
+// Lookup returns the values of the generated enum t, if t is one.
+func (r Result) Lookup(t types.Type) (*ValuesFact, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	fact, ok := r[named.Obj()]
+	return fact, ok
+}
+
+// Valid reports whether s would be accepted by the generated Parse method.
+func (f *ValuesFact) Valid(s string) bool {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for _, key := range f.Keys {
+		if key == lower {
+			return true
+		}
+	}
+	for _, slug := range f.Slugs {
+		if strings.EqualFold(s, slug) {
+			return true
+		}
+	}
+	return false
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	result := Result{}
+	for _, f := range pass.AllObjectFacts() {
+		if tn, ok := f.Object.(*types.TypeName); ok {
+			result[tn] = f.Fact.(*ValuesFact)
+		}
+	}
+
+	for tn, fact := range collect(pass) {
+		pass.ExportObjectFact(tn, fact)
+		result[tn] = fact
+	}
+	return result, nil
+}
+
+// collect finds the generated enums declared in the package being analyzed.
+func collect(pass *analysis.Pass) map[*types.TypeName]*ValuesFact {
+	facts := map[*types.TypeName]*ValuesFact{}
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || (gen.Tok != token.VAR && gen.Tok != token.CONST) {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i := range vs.Names {
+					tn, slug, ok := member(pass, vs, i)
+					if !ok {
+						continue
+					}
+					fact := facts[tn]
+					if fact == nil {
+						fact = &ValuesFact{Name: tn.Name(), Members: map[string]string{}}
+						facts[tn] = fact
+					}
+					fact.Slugs = append(fact.Slugs, slug)
+					fact.Members[slug] = vs.Names[i].Name
+				}
+			}
+		}
+	}
+	collectTables(pass, facts)
+	return facts
+}
+
+// collectTables records in facts the keys of the generated lookup tables,
+// i.e. the variables named like statusLookup holding a map[string]Status
+// literal. The int-style enums are found from them too, their slugs being
+// those of the name table of String.
+func collectTables(pass *analysis.Pass, facts map[*types.TypeName]*ValuesFact) {
+	var names []string
+	tables := map[string]*ast.CompositeLit{}
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i >= len(vs.Values) {
+						continue
+					}
+					if lit, ok := vs.Values[i].(*ast.CompositeLit); ok {
+						names = append(names, name.Name)
+						tables[name.Name] = lit
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !strings.HasSuffix(name, "Lookup") {
+			continue
+		}
+		lit := tables[name]
+		m, ok := pass.TypesInfo.TypeOf(lit).(*types.Map)
+		if !ok || !isString(m.Key()) {
+			continue
+		}
+		tn, ok := enumType(pass.Pkg, m.Elem())
+		if !ok {
+			continue
+		}
+		fact := facts[tn]
+		if fact == nil && !isString(tn.Type().Underlying()) {
+			fact = intFact(pass, tn, strings.TrimSuffix(name, "Lookup"), lit, tables)
+			if fact == nil {
+				continue
+			}
+			facts[tn] = fact
+		}
+		if fact == nil {
+			continue
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if tv := pass.TypesInfo.Types[kv.Key]; tv.Value != nil && tv.Value.Kind() == constant.String {
+				fact.Keys = append(fact.Keys, strings.ToLower(constant.StringVal(tv.Value)))
+			}
+		}
+	}
+}
+
+// intFact returns the values of the int-style enum tn, whose variables are
+// named after prefix: the slugs are cut from the <prefix>Names constant at
+// the offsets of <prefix>NameIndex, and the members are the constants the
+// lookup table maps to. It returns nil if the tables don't have that shape.
+func intFact(pass *analysis.Pass, tn *types.TypeName, prefix string, lookup *ast.CompositeLit, tables map[string]*ast.CompositeLit) *ValuesFact {
+	names, ok := pass.Pkg.Scope().Lookup(prefix + "Names").(*types.Const)
+	index := tables[prefix+"NameIndex"]
+	if !ok || names.Val().Kind() != constant.String || index == nil {
+		return nil
+	}
+	table := constant.StringVal(names.Val())
+	var offsets []int
+	for _, elt := range index.Elts {
+		tv := pass.TypesInfo.Types[elt]
+		if tv.Value == nil || tv.Value.Kind() != constant.Int {
+			return nil
+		}
+		offset, ok := constant.Int64Val(tv.Value)
+		if !ok || offset < 0 || offset > int64(len(table)) {
+			return nil
+		}
+		offsets = append(offsets, int(offset))
+	}
+
+	members := map[int64]string{}
+	for _, elt := range lookup.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		id, ok := kv.Value.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		if c, ok := pass.TypesInfo.Uses[id].(*types.Const); ok && c.Val().Kind() == constant.Int {
+			if i, ok := constant.Int64Val(c.Val()); ok {
+				members[i] = id.Name
+			}
+		}
+	}
+
+	fact := &ValuesFact{Name: tn.Name(), Members: map[string]string{}, Int: true}
+	for i := 0; i+1 < len(offsets); i++ {
+		member, ok := members[int64(i)]
+		if !ok || offsets[i] > offsets[i+1] {
+			return nil
+		}
+		slug := table[offsets[i]:offsets[i+1]]
+		fact.Slugs = append(fact.Slugs, slug)
+		fact.Members[slug] = member
+	}
+	if len(fact.Slugs) == 0 {
+		return nil
+	}
+	return fact
+}
+
+// member reports whether the i-th name of vs is a member definition of a
+// generated enum, i.e. either a variable initialized by a composite literal
+// like Status{"active"} or a constant like Status("active").
+func member(pass *analysis.Pass, vs *ast.ValueSpec, i int) (*types.TypeName, string, bool) {
+	if c, ok := pass.TypesInfo.Defs[vs.Names[i]].(*types.Const); ok {
+		tn, ok := enumType(pass.Pkg, c.Type())
+		if !ok || c.Val().Kind() != constant.String {
+			return nil, "", false
+		}
+		return tn, constant.StringVal(c.Val()), true
+	}
+
+	if i >= len(vs.Values) {
+		return nil, "", false
+	}
+	lit, ok := vs.Values[i].(*ast.CompositeLit)
+	if !ok || len(lit.Elts) != 1 {
+		return nil, "", false
+	}
+	tn, ok := enumType(pass.Pkg, pass.TypesInfo.TypeOf(lit))
+	if !ok {
+		return nil, "", false
+	}
+	basic, ok := lit.Elts[0].(*ast.BasicLit)
+	if !ok || basic.Kind != token.STRING {
+		return nil, "", false
+	}
+	slug, err := strconv.Unquote(basic.Value)
+	if err != nil {
+		return nil, "", false
+	}
+	return tn, slug, true
+}
+
+// enumType reports whether t has the shape of a generated enum declared in pkg:
+// a named struct with a single "slug" string field (or a named string or
+// integer type) and a <Name>FromString constructor.
+func enumType(pkg *types.Package, t types.Type) (*types.TypeName, bool) {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != pkg {
+		return nil, false
+	}
+	switch u := named.Underlying().(type) {
+	case *types.Struct:
+		if u.NumFields() != 1 || u.Field(0).Name() != "slug" || !isString(u.Field(0).Type()) {
+			return nil, false
+		}
+	case *types.Basic:
+		if !isString(u) && u.Info()&types.IsInteger == 0 {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	if _, ok := pkg.Scope().Lookup(named.Obj().Name() + "FromString").(*types.Func); !ok {
+		return nil, false
+	}
+	return named.Obj(), true
+}
+
+func isString(t types.Type) bool {
+	basic, ok := t.(*types.Basic)
+	return ok && basic.Kind() == types.String
+}
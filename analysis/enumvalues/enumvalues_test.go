@@ -0,0 +1,77 @@
+package enumvalues_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/panta/go-safe-enum-generator/analysis/enumvalues"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer checks the facts collected from testdata/src/a/a_gen.go, the
+// code generated for a.go in each style and naming: it's to be regenerated
+// (keeping the want comments of the types) when the template changes.
+func TestAnalyzer(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), enumvalues.Analyzer, "a")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	facts := map[string]*enumvalues.ValuesFact{}
+	for tn, fact := range results[0].Result.(enumvalues.Result) {
+		facts[tn.Name()] = fact
+	}
+
+	want := map[string]*enumvalues.ValuesFact{
+		"Color": {
+			Name:    "Color",
+			Slugs:   []string{"red", "green", "blue"},
+			Members: map[string]string{"red": "ColorRed", "green": "ColorGreen", "blue": "ColorBlue"},
+			Keys:    []string{"red", "green", "blue"},
+		},
+		"Shape": {
+			Name:    "Shape",
+			Slugs:   []string{"circle", "square"},
+			Members: map[string]string{"circle": "ShapeCircle", "square": "ShapeSquare"},
+			Keys:    []string{"circle", "square"},
+		},
+		"Level": {
+			Name:    "Level",
+			Slugs:   []string{"low", "high"},
+			Members: map[string]string{"low": "LevelLow", "high": "LevelHigh"},
+			Keys:    []string{"low", "high"},
+			Int:     true,
+		},
+		"Mode": {
+			Name:    "Mode",
+			Slugs:   []string{"on", "off"},
+			Members: map[string]string{"on": "ModeOn", "off": "ModeOff"},
+			Keys:    []string{"on", "off"},
+		},
+		"Tier": {
+			Name:    "Tier",
+			Slugs:   []string{"free", "pro"},
+			Members: map[string]string{"free": "TierFree", "pro": "TierPro"},
+			Keys:    []string{"free", "pro"},
+			Int:     true,
+		},
+		"AuthType": {
+			Name:    "AuthType",
+			Slugs:   []string{"plain", "digest-md5"},
+			Members: map[string]string{"plain": "AuthTypePlain", "digest-md5": "AuthTypeDigestMd5"},
+			Keys:    []string{"plain", "digest-md5", "auth_type_plain", "auth_type_digest_md5", "digest_md5"},
+		},
+	}
+	for name, w := range want {
+		got, ok := facts[name]
+		if !ok {
+			t.Errorf("no fact for %s", name)
+			continue
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("fact of %s = %+v, want %+v", name, *got, *w)
+		}
+	}
+	if len(facts) != len(want) {
+		t.Errorf("got facts for %d enums, want %d", len(facts), len(want))
+	}
+}
@@ -0,0 +1,13 @@
+package a
+
+// ENUM Color (red, green, blue)
+
+// ENUM Shape (circle, square) style=const
+
+// ENUM Level (low, high) style=int
+
+// ENUM Mode (on, off) var-names=namespaced
+
+// ENUM Tier (free, pro) style=int var-names=namespaced
+
+// ENUM AuthType (plain, digest-md5) protojson
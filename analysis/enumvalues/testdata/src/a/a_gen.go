@@ -0,0 +1,1428 @@
+// Code generated by go-safe-enum-generator. DO NOT EDIT.
+
+package a
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Color is an enum.
+// Possible values: red, green, blue
+// see https://threedots.tech/post/safer-enums-in-go/
+type Color struct { // want Color:`enum\(red, green, blue\)`
+	slug string
+}
+
+// String returns the string representation of a Color enum.
+func (e Color) String() string {
+	return e.slug
+}
+
+// IsValid reports whether the enum holds one of the declared values.
+func (e Color) IsValid() bool {
+	for _, v := range colorValues {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Ptr returns a pointer to a copy of the enum value.
+func (e Color) Ptr() *Color {
+	return &e
+}
+
+// Parse sets the enum value from a string.
+// On failure the enum is left untouched.
+func (e *Color) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, ok := colorFind(s); ok {
+		*e = v
+		return nil
+	}
+
+	return fmt.Errorf("unknown color: %s", s)
+}
+
+// colorFind returns the Color whose lookup key equals s
+// under case folding: the exact key is tried first, then each key in turn.
+func colorFind(s string) (Color, bool) {
+	if v, ok := colorLookup[s]; ok {
+		return v, true
+	}
+	for k, v := range colorLookup {
+		if strings.EqualFold(k, s) {
+			return v, true
+		}
+	}
+	var zero Color
+	return zero, false
+}
+
+// ColorFromString returns a Color from a string.
+func ColorFromString(s string) (Color, error) {
+	var e Color
+	err := e.Parse(s)
+	return e, err
+}
+
+// ColorFromInt returns a Color from a numeric value.
+func ColorFromInt(value int) (Color, error) {
+	if v, ok := colorIntMap[value]; ok {
+		return v, nil
+	}
+	var zero Color
+	return zero, fmt.Errorf("can't convert the value %d to a Color", value)
+}
+
+// ColorSchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
+func ColorSchemaConverter(value string) reflect.Value {
+	var e Color
+	if err := e.Parse(value); err != nil {
+		return reflect.ValueOf(nil)
+	}
+	return reflect.ValueOf(e)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (e Color) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (e *Color) Scan(value interface{}) error {
+	if value == nil {
+		*e = ColorRed
+		return nil
+	}
+
+	// drivers like clickhouse-go pass sized integers
+	switch v := value.(type) {
+	case int8:
+		value = int(v)
+	case int16:
+		value = int(v)
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	}
+
+	switch v := value.(type) {
+	default:
+		return fmt.Errorf("can't convert to Color, unexpected type %T", v)
+	case int:
+		if found, ok := colorIntMap[v]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %d for Color", v)
+		}
+	case float64:
+		if found, ok := colorIntMap[int(v)]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %f for Color", v)
+		}
+	case []byte:
+		if err := e.Parse(string(v)); err != nil {
+			return fmt.Errorf("can't parse Color: %w", err)
+		}
+		return nil
+	case *string:
+		if err := e.Parse(*v); err != nil {
+			return fmt.Errorf("can't parse Color: %w", err)
+		}
+		return nil
+	case string:
+		if err := e.Parse(v); err != nil {
+			return fmt.Errorf("can't parse Color: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't convert to Color, unexpected type %T", value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Color) MarshalJSON() ([]byte, error) {
+	if m, ok := colorMarshaled[e]; ok {
+		return append([]byte(nil), m.json...), nil
+	}
+	return json.Marshal(e.String())
+}
+
+// AppendJSON appends the JSON encoding of the enum to b, without allocating
+// for the declared values.
+func (e Color) AppendJSON(b []byte) []byte {
+	if m, ok := colorMarshaled[e]; ok {
+		return append(b, m.json...)
+	}
+	data, _ := json.Marshal(e.String())
+	return append(b, data...)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Color) UnmarshalJSON(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal nil JSON into Color")
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if err := e.Parse(text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalText implements the text marshaller method.
+func (e Color) MarshalText() ([]byte, error) {
+	if m, ok := colorMarshaled[e]; ok {
+		return append([]byte(nil), m.text...), nil
+	}
+	return []byte(e.String()), nil
+}
+
+// AppendText implements the encoding.TextAppender interface (Go 1.24),
+// appending the text of the enum to b without allocating.
+func (e Color) AppendText(b []byte) ([]byte, error) {
+	return append(b, e.String()...), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (e *Color) UnmarshalText(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal empty text into Color")
+	}
+	if err := e.Parse(string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the list of possible values for the enum.
+func (e Color) Values() []Color {
+	return ColorValues()
+}
+
+// ColorValues returns the list of possible values for the Color enum.
+// The slice is a new copy at each call: use ColorValuesArray
+// in hot paths.
+func ColorValues() []Color {
+	return append([]Color{}, colorValues[:]...)
+}
+
+// ColorValuesArray returns the possible values for the Color enum
+// as an array, which is copied without allocating.
+func ColorValuesArray() [3]Color {
+	return colorValues
+}
+
+var (
+	colorValues = [...]Color{ColorRed, ColorGreen, ColorBlue}
+	ColorRed    = Color{"red"}
+	ColorGreen  = Color{"green"}
+	ColorBlue   = Color{"blue"}
+	colorIntMap = map[int]Color{
+		0: ColorRed,
+		1: ColorGreen,
+		2: ColorBlue,
+	}
+	colorLookup = map[string]Color{
+		"red":   ColorRed,
+		"green": ColorGreen,
+		"blue":  ColorBlue,
+	}
+	colorMarshaled = map[Color]struct{ text, json []byte }{
+		ColorRed:   {[]byte("red"), []byte("\"red\"")},
+		ColorGreen: {[]byte("green"), []byte("\"green\"")},
+		ColorBlue:  {[]byte("blue"), []byte("\"blue\"")},
+	}
+)
+
+// Shape is an enum.
+// Possible values: circle, square
+type Shape string // want Shape:`enum\(circle, square\)`
+
+// String returns the string representation of a Shape enum.
+func (e Shape) String() string {
+	return string(e)
+}
+
+// IsValid reports whether the enum holds one of the declared values.
+func (e Shape) IsValid() bool {
+	for _, v := range shapeValues {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Ptr returns a pointer to a copy of the enum value.
+func (e Shape) Ptr() *Shape {
+	return &e
+}
+
+// Parse sets the enum value from a string.
+// On failure the enum is left untouched.
+func (e *Shape) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, ok := shapeFind(s); ok {
+		*e = v
+		return nil
+	}
+
+	return fmt.Errorf("unknown shape: %s", s)
+}
+
+// shapeFind returns the Shape whose lookup key equals s
+// under case folding: the exact key is tried first, then each key in turn.
+func shapeFind(s string) (Shape, bool) {
+	if v, ok := shapeLookup[s]; ok {
+		return v, true
+	}
+	for k, v := range shapeLookup {
+		if strings.EqualFold(k, s) {
+			return v, true
+		}
+	}
+	var zero Shape
+	return zero, false
+}
+
+// ShapeFromString returns a Shape from a string.
+func ShapeFromString(s string) (Shape, error) {
+	var e Shape
+	err := e.Parse(s)
+	return e, err
+}
+
+// ShapeFromInt returns a Shape from a numeric value.
+func ShapeFromInt(value int) (Shape, error) {
+	if v, ok := shapeIntMap[value]; ok {
+		return v, nil
+	}
+	var zero Shape
+	return zero, fmt.Errorf("can't convert the value %d to a Shape", value)
+}
+
+// ShapeSchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
+func ShapeSchemaConverter(value string) reflect.Value {
+	var e Shape
+	if err := e.Parse(value); err != nil {
+		return reflect.ValueOf(nil)
+	}
+	return reflect.ValueOf(e)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (e Shape) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (e *Shape) Scan(value interface{}) error {
+	if value == nil {
+		*e = ShapeCircle
+		return nil
+	}
+
+	// drivers like clickhouse-go pass sized integers
+	switch v := value.(type) {
+	case int8:
+		value = int(v)
+	case int16:
+		value = int(v)
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	}
+
+	switch v := value.(type) {
+	default:
+		return fmt.Errorf("can't convert to Shape, unexpected type %T", v)
+	case int:
+		if found, ok := shapeIntMap[v]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %d for Shape", v)
+		}
+	case float64:
+		if found, ok := shapeIntMap[int(v)]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %f for Shape", v)
+		}
+	case []byte:
+		if err := e.Parse(string(v)); err != nil {
+			return fmt.Errorf("can't parse Shape: %w", err)
+		}
+		return nil
+	case *string:
+		if err := e.Parse(*v); err != nil {
+			return fmt.Errorf("can't parse Shape: %w", err)
+		}
+		return nil
+	case string:
+		if err := e.Parse(v); err != nil {
+			return fmt.Errorf("can't parse Shape: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't convert to Shape, unexpected type %T", value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Shape) MarshalJSON() ([]byte, error) {
+	if m, ok := shapeMarshaled[e]; ok {
+		return append([]byte(nil), m.json...), nil
+	}
+	return json.Marshal(e.String())
+}
+
+// AppendJSON appends the JSON encoding of the enum to b, without allocating
+// for the declared values.
+func (e Shape) AppendJSON(b []byte) []byte {
+	if m, ok := shapeMarshaled[e]; ok {
+		return append(b, m.json...)
+	}
+	data, _ := json.Marshal(e.String())
+	return append(b, data...)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Shape) UnmarshalJSON(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal nil JSON into Shape")
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if err := e.Parse(text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalText implements the text marshaller method.
+func (e Shape) MarshalText() ([]byte, error) {
+	if m, ok := shapeMarshaled[e]; ok {
+		return append([]byte(nil), m.text...), nil
+	}
+	return []byte(e.String()), nil
+}
+
+// AppendText implements the encoding.TextAppender interface (Go 1.24),
+// appending the text of the enum to b without allocating.
+func (e Shape) AppendText(b []byte) ([]byte, error) {
+	return append(b, e.String()...), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (e *Shape) UnmarshalText(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal empty text into Shape")
+	}
+	if err := e.Parse(string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the list of possible values for the enum.
+func (e Shape) Values() []Shape {
+	return ShapeValues()
+}
+
+// ShapeValues returns the list of possible values for the Shape enum.
+// The slice is a new copy at each call: use ShapeValuesArray
+// in hot paths.
+func ShapeValues() []Shape {
+	return append([]Shape{}, shapeValues[:]...)
+}
+
+// ShapeValuesArray returns the possible values for the Shape enum
+// as an array, which is copied without allocating.
+func ShapeValuesArray() [2]Shape {
+	return shapeValues
+}
+
+const (
+	ShapeCircle Shape = "circle"
+	ShapeSquare Shape = "square"
+)
+
+var (
+	shapeValues = [...]Shape{ShapeCircle, ShapeSquare}
+	shapeIntMap = map[int]Shape{
+		0: ShapeCircle,
+		1: ShapeSquare,
+	}
+	shapeLookup = map[string]Shape{
+		"circle": ShapeCircle,
+		"square": ShapeSquare,
+	}
+	shapeMarshaled = map[Shape]struct{ text, json []byte }{
+		ShapeCircle: {[]byte("circle"), []byte("\"circle\"")},
+		ShapeSquare: {[]byte("square"), []byte("\"square\"")},
+	}
+)
+
+// Level is an enum.
+// Possible values: low, high
+type Level int // want Level:`enum\(low, high\)`
+
+// String returns the string representation of a Level enum.
+func (e Level) String() string {
+	if e < 0 || int(e) >= len(levelNameIndex)-1 {
+		return fmt.Sprintf("Level(%d)", int(e))
+	}
+	return levelNames[levelNameIndex[e]:levelNameIndex[e+1]]
+}
+
+// IsValid reports whether the enum holds one of the declared values.
+func (e Level) IsValid() bool {
+	for _, v := range levelValues {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Ptr returns a pointer to a copy of the enum value.
+func (e Level) Ptr() *Level {
+	return &e
+}
+
+// Parse sets the enum value from a string.
+// On failure the enum is left untouched.
+func (e *Level) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, ok := levelFind(s); ok {
+		*e = v
+		return nil
+	}
+
+	return fmt.Errorf("unknown level: %s", s)
+}
+
+// levelFind returns the Level whose lookup key equals s
+// under case folding: the exact key is tried first, then each key in turn.
+func levelFind(s string) (Level, bool) {
+	if v, ok := levelLookup[s]; ok {
+		return v, true
+	}
+	for k, v := range levelLookup {
+		if strings.EqualFold(k, s) {
+			return v, true
+		}
+	}
+	var zero Level
+	return zero, false
+}
+
+// LevelFromString returns a Level from a string.
+func LevelFromString(s string) (Level, error) {
+	var e Level
+	err := e.Parse(s)
+	return e, err
+}
+
+// LevelFromInt returns a Level from a numeric value.
+func LevelFromInt(value int) (Level, error) {
+	if v, ok := levelIntMap[value]; ok {
+		return v, nil
+	}
+	var zero Level
+	return zero, fmt.Errorf("can't convert the value %d to a Level", value)
+}
+
+// LevelSchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
+func LevelSchemaConverter(value string) reflect.Value {
+	var e Level
+	if err := e.Parse(value); err != nil {
+		return reflect.ValueOf(nil)
+	}
+	return reflect.ValueOf(e)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (e Level) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (e *Level) Scan(value interface{}) error {
+	if value == nil {
+		*e = LevelLow
+		return nil
+	}
+
+	// drivers like clickhouse-go pass sized integers
+	switch v := value.(type) {
+	case int8:
+		value = int(v)
+	case int16:
+		value = int(v)
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	}
+
+	switch v := value.(type) {
+	default:
+		return fmt.Errorf("can't convert to Level, unexpected type %T", v)
+	case int:
+		if found, ok := levelIntMap[v]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %d for Level", v)
+		}
+	case float64:
+		if found, ok := levelIntMap[int(v)]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %f for Level", v)
+		}
+	case []byte:
+		if err := e.Parse(string(v)); err != nil {
+			return fmt.Errorf("can't parse Level: %w", err)
+		}
+		return nil
+	case *string:
+		if err := e.Parse(*v); err != nil {
+			return fmt.Errorf("can't parse Level: %w", err)
+		}
+		return nil
+	case string:
+		if err := e.Parse(v); err != nil {
+			return fmt.Errorf("can't parse Level: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't convert to Level, unexpected type %T", value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Level) MarshalJSON() ([]byte, error) {
+	if m, ok := levelMarshaled[e]; ok {
+		return append([]byte(nil), m.json...), nil
+	}
+	return json.Marshal(e.String())
+}
+
+// AppendJSON appends the JSON encoding of the enum to b, without allocating
+// for the declared values.
+func (e Level) AppendJSON(b []byte) []byte {
+	if m, ok := levelMarshaled[e]; ok {
+		return append(b, m.json...)
+	}
+	data, _ := json.Marshal(e.String())
+	return append(b, data...)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Level) UnmarshalJSON(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal nil JSON into Level")
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if err := e.Parse(text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalText implements the text marshaller method.
+func (e Level) MarshalText() ([]byte, error) {
+	if m, ok := levelMarshaled[e]; ok {
+		return append([]byte(nil), m.text...), nil
+	}
+	return []byte(e.String()), nil
+}
+
+// AppendText implements the encoding.TextAppender interface (Go 1.24),
+// appending the text of the enum to b without allocating.
+func (e Level) AppendText(b []byte) ([]byte, error) {
+	return append(b, e.String()...), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (e *Level) UnmarshalText(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal empty text into Level")
+	}
+	if err := e.Parse(string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the list of possible values for the enum.
+func (e Level) Values() []Level {
+	return LevelValues()
+}
+
+// LevelValues returns the list of possible values for the Level enum.
+// The slice is a new copy at each call: use LevelValuesArray
+// in hot paths.
+func LevelValues() []Level {
+	return append([]Level{}, levelValues[:]...)
+}
+
+// LevelValuesArray returns the possible values for the Level enum
+// as an array, which is copied without allocating.
+func LevelValuesArray() [2]Level {
+	return levelValues
+}
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+
+const levelNames = "lowhigh"
+
+var levelNameIndex = [...]uint8{0, 3, 7}
+
+var (
+	levelValues = [...]Level{LevelLow, LevelHigh}
+	levelIntMap = map[int]Level{
+		0: LevelLow,
+		1: LevelHigh,
+	}
+	levelLookup = map[string]Level{
+		"low":  LevelLow,
+		"high": LevelHigh,
+	}
+	levelMarshaled = map[Level]struct{ text, json []byte }{
+		LevelLow:  {[]byte("low"), []byte("\"low\"")},
+		LevelHigh: {[]byte("high"), []byte("\"high\"")},
+	}
+)
+
+// Mode is an enum.
+// Possible values: on, off
+// see https://threedots.tech/post/safer-enums-in-go/
+type Mode struct { // want Mode:`enum\(on, off\)`
+	slug string
+}
+
+// String returns the string representation of a Mode enum.
+func (e Mode) String() string {
+	return e.slug
+}
+
+// IsValid reports whether the enum holds one of the declared values.
+func (e Mode) IsValid() bool {
+	for _, v := range _enumModeValues {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Ptr returns a pointer to a copy of the enum value.
+func (e Mode) Ptr() *Mode {
+	return &e
+}
+
+// Parse sets the enum value from a string.
+// On failure the enum is left untouched.
+func (e *Mode) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, ok := _enumModeFind(s); ok {
+		*e = v
+		return nil
+	}
+
+	return fmt.Errorf("unknown mode: %s", s)
+}
+
+// _enumModeFind returns the Mode whose lookup key equals s
+// under case folding: the exact key is tried first, then each key in turn.
+func _enumModeFind(s string) (Mode, bool) {
+	if v, ok := _enumModeLookup[s]; ok {
+		return v, true
+	}
+	for k, v := range _enumModeLookup {
+		if strings.EqualFold(k, s) {
+			return v, true
+		}
+	}
+	var zero Mode
+	return zero, false
+}
+
+// ModeFromString returns a Mode from a string.
+func ModeFromString(s string) (Mode, error) {
+	var e Mode
+	err := e.Parse(s)
+	return e, err
+}
+
+// ModeFromInt returns a Mode from a numeric value.
+func ModeFromInt(value int) (Mode, error) {
+	if v, ok := _enumModeIntMap[value]; ok {
+		return v, nil
+	}
+	var zero Mode
+	return zero, fmt.Errorf("can't convert the value %d to a Mode", value)
+}
+
+// ModeSchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
+func ModeSchemaConverter(value string) reflect.Value {
+	var e Mode
+	if err := e.Parse(value); err != nil {
+		return reflect.ValueOf(nil)
+	}
+	return reflect.ValueOf(e)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (e Mode) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (e *Mode) Scan(value interface{}) error {
+	if value == nil {
+		*e = ModeOn
+		return nil
+	}
+
+	// drivers like clickhouse-go pass sized integers
+	switch v := value.(type) {
+	case int8:
+		value = int(v)
+	case int16:
+		value = int(v)
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	}
+
+	switch v := value.(type) {
+	default:
+		return fmt.Errorf("can't convert to Mode, unexpected type %T", v)
+	case int:
+		if found, ok := _enumModeIntMap[v]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %d for Mode", v)
+		}
+	case float64:
+		if found, ok := _enumModeIntMap[int(v)]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %f for Mode", v)
+		}
+	case []byte:
+		if err := e.Parse(string(v)); err != nil {
+			return fmt.Errorf("can't parse Mode: %w", err)
+		}
+		return nil
+	case *string:
+		if err := e.Parse(*v); err != nil {
+			return fmt.Errorf("can't parse Mode: %w", err)
+		}
+		return nil
+	case string:
+		if err := e.Parse(v); err != nil {
+			return fmt.Errorf("can't parse Mode: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't convert to Mode, unexpected type %T", value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Mode) MarshalJSON() ([]byte, error) {
+	if m, ok := _enumModeMarshaled[e]; ok {
+		return append([]byte(nil), m.json...), nil
+	}
+	return json.Marshal(e.String())
+}
+
+// AppendJSON appends the JSON encoding of the enum to b, without allocating
+// for the declared values.
+func (e Mode) AppendJSON(b []byte) []byte {
+	if m, ok := _enumModeMarshaled[e]; ok {
+		return append(b, m.json...)
+	}
+	data, _ := json.Marshal(e.String())
+	return append(b, data...)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Mode) UnmarshalJSON(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal nil JSON into Mode")
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if err := e.Parse(text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalText implements the text marshaller method.
+func (e Mode) MarshalText() ([]byte, error) {
+	if m, ok := _enumModeMarshaled[e]; ok {
+		return append([]byte(nil), m.text...), nil
+	}
+	return []byte(e.String()), nil
+}
+
+// AppendText implements the encoding.TextAppender interface (Go 1.24),
+// appending the text of the enum to b without allocating.
+func (e Mode) AppendText(b []byte) ([]byte, error) {
+	return append(b, e.String()...), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (e *Mode) UnmarshalText(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal empty text into Mode")
+	}
+	if err := e.Parse(string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the list of possible values for the enum.
+func (e Mode) Values() []Mode {
+	return ModeValues()
+}
+
+// ModeValues returns the list of possible values for the Mode enum.
+// The slice is a new copy at each call: use ModeValuesArray
+// in hot paths.
+func ModeValues() []Mode {
+	return append([]Mode{}, _enumModeValues[:]...)
+}
+
+// ModeValuesArray returns the possible values for the Mode enum
+// as an array, which is copied without allocating.
+func ModeValuesArray() [2]Mode {
+	return _enumModeValues
+}
+
+var (
+	_enumModeValues = [...]Mode{ModeOn, ModeOff}
+	ModeOn          = Mode{"on"}
+	ModeOff         = Mode{"off"}
+	_enumModeIntMap = map[int]Mode{
+		0: ModeOn,
+		1: ModeOff,
+	}
+	_enumModeLookup = map[string]Mode{
+		"on":  ModeOn,
+		"off": ModeOff,
+	}
+	_enumModeMarshaled = map[Mode]struct{ text, json []byte }{
+		ModeOn:  {[]byte("on"), []byte("\"on\"")},
+		ModeOff: {[]byte("off"), []byte("\"off\"")},
+	}
+)
+
+// Tier is an enum.
+// Possible values: free, pro
+type Tier int // want Tier:`enum\(free, pro\)`
+
+// String returns the string representation of a Tier enum.
+func (e Tier) String() string {
+	if e < 0 || int(e) >= len(_enumTierNameIndex)-1 {
+		return fmt.Sprintf("Tier(%d)", int(e))
+	}
+	return _enumTierNames[_enumTierNameIndex[e]:_enumTierNameIndex[e+1]]
+}
+
+// IsValid reports whether the enum holds one of the declared values.
+func (e Tier) IsValid() bool {
+	for _, v := range _enumTierValues {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Ptr returns a pointer to a copy of the enum value.
+func (e Tier) Ptr() *Tier {
+	return &e
+}
+
+// Parse sets the enum value from a string.
+// On failure the enum is left untouched.
+func (e *Tier) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, ok := _enumTierFind(s); ok {
+		*e = v
+		return nil
+	}
+
+	return fmt.Errorf("unknown tier: %s", s)
+}
+
+// _enumTierFind returns the Tier whose lookup key equals s
+// under case folding: the exact key is tried first, then each key in turn.
+func _enumTierFind(s string) (Tier, bool) {
+	if v, ok := _enumTierLookup[s]; ok {
+		return v, true
+	}
+	for k, v := range _enumTierLookup {
+		if strings.EqualFold(k, s) {
+			return v, true
+		}
+	}
+	var zero Tier
+	return zero, false
+}
+
+// TierFromString returns a Tier from a string.
+func TierFromString(s string) (Tier, error) {
+	var e Tier
+	err := e.Parse(s)
+	return e, err
+}
+
+// TierFromInt returns a Tier from a numeric value.
+func TierFromInt(value int) (Tier, error) {
+	if v, ok := _enumTierIntMap[value]; ok {
+		return v, nil
+	}
+	var zero Tier
+	return zero, fmt.Errorf("can't convert the value %d to a Tier", value)
+}
+
+// TierSchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
+func TierSchemaConverter(value string) reflect.Value {
+	var e Tier
+	if err := e.Parse(value); err != nil {
+		return reflect.ValueOf(nil)
+	}
+	return reflect.ValueOf(e)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (e Tier) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (e *Tier) Scan(value interface{}) error {
+	if value == nil {
+		*e = TierFree
+		return nil
+	}
+
+	// drivers like clickhouse-go pass sized integers
+	switch v := value.(type) {
+	case int8:
+		value = int(v)
+	case int16:
+		value = int(v)
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	}
+
+	switch v := value.(type) {
+	default:
+		return fmt.Errorf("can't convert to Tier, unexpected type %T", v)
+	case int:
+		if found, ok := _enumTierIntMap[v]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %d for Tier", v)
+		}
+	case float64:
+		if found, ok := _enumTierIntMap[int(v)]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %f for Tier", v)
+		}
+	case []byte:
+		if err := e.Parse(string(v)); err != nil {
+			return fmt.Errorf("can't parse Tier: %w", err)
+		}
+		return nil
+	case *string:
+		if err := e.Parse(*v); err != nil {
+			return fmt.Errorf("can't parse Tier: %w", err)
+		}
+		return nil
+	case string:
+		if err := e.Parse(v); err != nil {
+			return fmt.Errorf("can't parse Tier: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't convert to Tier, unexpected type %T", value)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e Tier) MarshalJSON() ([]byte, error) {
+	if m, ok := _enumTierMarshaled[e]; ok {
+		return append([]byte(nil), m.json...), nil
+	}
+	return json.Marshal(e.String())
+}
+
+// AppendJSON appends the JSON encoding of the enum to b, without allocating
+// for the declared values.
+func (e Tier) AppendJSON(b []byte) []byte {
+	if m, ok := _enumTierMarshaled[e]; ok {
+		return append(b, m.json...)
+	}
+	data, _ := json.Marshal(e.String())
+	return append(b, data...)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Tier) UnmarshalJSON(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal nil JSON into Tier")
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if err := e.Parse(text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalText implements the text marshaller method.
+func (e Tier) MarshalText() ([]byte, error) {
+	if m, ok := _enumTierMarshaled[e]; ok {
+		return append([]byte(nil), m.text...), nil
+	}
+	return []byte(e.String()), nil
+}
+
+// AppendText implements the encoding.TextAppender interface (Go 1.24),
+// appending the text of the enum to b without allocating.
+func (e Tier) AppendText(b []byte) ([]byte, error) {
+	return append(b, e.String()...), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (e *Tier) UnmarshalText(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal empty text into Tier")
+	}
+	if err := e.Parse(string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the list of possible values for the enum.
+func (e Tier) Values() []Tier {
+	return TierValues()
+}
+
+// TierValues returns the list of possible values for the Tier enum.
+// The slice is a new copy at each call: use TierValuesArray
+// in hot paths.
+func TierValues() []Tier {
+	return append([]Tier{}, _enumTierValues[:]...)
+}
+
+// TierValuesArray returns the possible values for the Tier enum
+// as an array, which is copied without allocating.
+func TierValuesArray() [2]Tier {
+	return _enumTierValues
+}
+
+const (
+	TierFree Tier = iota
+	TierPro
+)
+
+const _enumTierNames = "freepro"
+
+var _enumTierNameIndex = [...]uint8{0, 4, 7}
+
+var (
+	_enumTierValues = [...]Tier{TierFree, TierPro}
+	_enumTierIntMap = map[int]Tier{
+		0: TierFree,
+		1: TierPro,
+	}
+	_enumTierLookup = map[string]Tier{
+		"free": TierFree,
+		"pro":  TierPro,
+	}
+	_enumTierMarshaled = map[Tier]struct{ text, json []byte }{
+		TierFree: {[]byte("free"), []byte("\"free\"")},
+		TierPro:  {[]byte("pro"), []byte("\"pro\"")},
+	}
+)
+
+// AuthType is an enum.
+// Possible values: plain, digest-md5
+// see https://threedots.tech/post/safer-enums-in-go/
+type AuthType struct { // want AuthType:`enum\(plain, digest-md5\)`
+	slug string
+}
+
+// String returns the string representation of a AuthType enum.
+func (e AuthType) String() string {
+	return e.slug
+}
+
+// IsValid reports whether the enum holds one of the declared values.
+func (e AuthType) IsValid() bool {
+	for _, v := range authtypeValues {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Ptr returns a pointer to a copy of the enum value.
+func (e AuthType) Ptr() *AuthType {
+	return &e
+}
+
+// Parse sets the enum value from a string.
+// On failure the enum is left untouched.
+func (e *AuthType) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, ok := authtypeFind(s); ok {
+		*e = v
+		return nil
+	}
+
+	return fmt.Errorf("unknown authtype: %s", s)
+}
+
+// authtypeFind returns the AuthType whose lookup key equals s
+// under case folding: the exact key is tried first, then each key in turn.
+func authtypeFind(s string) (AuthType, bool) {
+	if v, ok := authtypeLookup[s]; ok {
+		return v, true
+	}
+	for k, v := range authtypeLookup {
+		if strings.EqualFold(k, s) {
+			return v, true
+		}
+	}
+	var zero AuthType
+	return zero, false
+}
+
+// AuthTypeFromString returns a AuthType from a string.
+func AuthTypeFromString(s string) (AuthType, error) {
+	var e AuthType
+	err := e.Parse(s)
+	return e, err
+}
+
+// AuthTypeFromInt returns a AuthType from a numeric value.
+func AuthTypeFromInt(value int) (AuthType, error) {
+	if v, ok := authtypeIntMap[value]; ok {
+		return v, nil
+	}
+	var zero AuthType
+	return zero, fmt.Errorf("can't convert the value %d to a AuthType", value)
+}
+
+// AuthTypeSchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
+func AuthTypeSchemaConverter(value string) reflect.Value {
+	var e AuthType
+	if err := e.Parse(value); err != nil {
+		return reflect.ValueOf(nil)
+	}
+	return reflect.ValueOf(e)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (e AuthType) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (e *AuthType) Scan(value interface{}) error {
+	if value == nil {
+		*e = AuthTypePlain
+		return nil
+	}
+
+	// drivers like clickhouse-go pass sized integers
+	switch v := value.(type) {
+	case int8:
+		value = int(v)
+	case int16:
+		value = int(v)
+	case int32:
+		value = int(v)
+	case int64:
+		value = int(v)
+	}
+
+	switch v := value.(type) {
+	default:
+		return fmt.Errorf("can't convert to AuthType, unexpected type %T", v)
+	case int:
+		if found, ok := authtypeIntMap[v]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %d for AuthType", v)
+		}
+	case float64:
+		if found, ok := authtypeIntMap[int(v)]; ok {
+			*e = found
+		} else {
+			return fmt.Errorf("invalid value %f for AuthType", v)
+		}
+	case []byte:
+		if err := e.Parse(string(v)); err != nil {
+			return fmt.Errorf("can't parse AuthType: %w", err)
+		}
+		return nil
+	case *string:
+		if err := e.Parse(*v); err != nil {
+			return fmt.Errorf("can't parse AuthType: %w", err)
+		}
+		return nil
+	case string:
+		if err := e.Parse(v); err != nil {
+			return fmt.Errorf("can't parse AuthType: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("can't convert to AuthType, unexpected type %T", value)
+}
+
+// ProtoJSONName returns the protobuf JSON name of the enum value
+// (e.g. AUTH_TYPE_PLAIN), which Parse also accepts.
+func (e AuthType) ProtoJSONName() string {
+	return authtypeProtoJSONNames[e]
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (e AuthType) MarshalJSON() ([]byte, error) {
+	if m, ok := authtypeMarshaled[e]; ok {
+		return append([]byte(nil), m.json...), nil
+	}
+	return json.Marshal(e.String())
+}
+
+// AppendJSON appends the JSON encoding of the enum to b, without allocating
+// for the declared values.
+func (e AuthType) AppendJSON(b []byte) []byte {
+	if m, ok := authtypeMarshaled[e]; ok {
+		return append(b, m.json...)
+	}
+	data, _ := json.Marshal(e.String())
+	return append(b, data...)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *AuthType) UnmarshalJSON(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal nil JSON into AuthType")
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if err := e.Parse(text); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarshalText implements the text marshaller method.
+func (e AuthType) MarshalText() ([]byte, error) {
+	if m, ok := authtypeMarshaled[e]; ok {
+		return append([]byte(nil), m.text...), nil
+	}
+	return []byte(e.String()), nil
+}
+
+// AppendText implements the encoding.TextAppender interface (Go 1.24),
+// appending the text of the enum to b without allocating.
+func (e AuthType) AppendText(b []byte) ([]byte, error) {
+	return append(b, e.String()...), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (e *AuthType) UnmarshalText(data []byte) error {
+	if data == nil {
+		return fmt.Errorf("can't unmarshal empty text into AuthType")
+	}
+	if err := e.Parse(string(data)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Values returns the list of possible values for the enum.
+func (e AuthType) Values() []AuthType {
+	return AuthTypeValues()
+}
+
+// AuthTypeValues returns the list of possible values for the AuthType enum.
+// The slice is a new copy at each call: use AuthTypeValuesArray
+// in hot paths.
+func AuthTypeValues() []AuthType {
+	return append([]AuthType{}, authtypeValues[:]...)
+}
+
+// AuthTypeValuesArray returns the possible values for the AuthType enum
+// as an array, which is copied without allocating.
+func AuthTypeValuesArray() [2]AuthType {
+	return authtypeValues
+}
+
+var (
+	authtypeValues    = [...]AuthType{AuthTypePlain, AuthTypeDigestMd5}
+	AuthTypePlain     = AuthType{"plain"}
+	AuthTypeDigestMd5 = AuthType{"digest-md5"}
+	authtypeIntMap    = map[int]AuthType{
+		0: AuthTypePlain,
+		1: AuthTypeDigestMd5,
+	}
+	authtypeLookup = map[string]AuthType{
+		"plain":                AuthTypePlain,
+		"digest-md5":           AuthTypeDigestMd5,
+		"auth_type_plain":      AuthTypePlain,
+		"auth_type_digest_md5": AuthTypeDigestMd5,
+		"digest_md5":           AuthTypeDigestMd5,
+	}
+	authtypeProtoJSONNames = map[AuthType]string{
+		AuthTypePlain:     "AUTH_TYPE_PLAIN",
+		AuthTypeDigestMd5: "AUTH_TYPE_DIGEST_MD5",
+	}
+	authtypeMarshaled = map[AuthType]struct{ text, json []byte }{
+		AuthTypePlain:     {[]byte("plain"), []byte("\"plain\"")},
+		AuthTypeDigestMd5: {[]byte("digest-md5"), []byte("\"digest-md5\"")},
+	}
+)
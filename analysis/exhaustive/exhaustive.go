@@ -0,0 +1,128 @@
+// Package exhaustive defines an Analyzer that reports switch statements over
+// generated enums that do not handle every member.
+//
+// The enums produced by go-safe-enum-generator are structs rather than
+// constants, so linters that only understand const-based enums can't check
+// them. Given
+//
+//	// ENUM Status (active, disabled, pending)
+//
+// the following switch is reported as missing StatusPending:
+//
+//	switch s {
+//	case StatusActive:
+//	case StatusDisabled:
+//	}
+package exhaustive
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"github.com/panta/go-safe-enum-generator/analysis/enumvalues"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports non-exhaustive switch statements over generated enums.
+var Analyzer = &analysis.Analyzer{
+	Name:     "enumexhaustive",
+	Doc:      "report switch statements over generated enums that miss members",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, enumvalues.Analyzer},
+}
+
+var defaultSignifiesExhaustive bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&defaultSignifiesExhaustive, "default-signifies-exhaustive", false,
+		"consider a switch with a default clause exhaustive")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	enums := pass.ResultOf[enumvalues.Analyzer].(enumvalues.Result)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.SwitchStmt)(nil)}, func(n ast.Node) {
+		sw := n.(*ast.SwitchStmt)
+		if sw.Tag == nil {
+			return
+		}
+		named, ok := pass.TypesInfo.TypeOf(sw.Tag).(*types.Named)
+		if !ok {
+			return
+		}
+		enum, ok := enums.Lookup(named)
+		if !ok {
+			return
+		}
+
+		handled := map[string]bool{}
+		for _, stmt := range sw.Body.List {
+			clause := stmt.(*ast.CaseClause)
+			if clause.List == nil && defaultSignifiesExhaustive {
+				return
+			}
+			for _, expr := range clause.List {
+				if name, ok := memberName(pass, named.Obj().Pkg(), enum, expr); ok {
+					handled[name] = true
+				}
+			}
+		}
+
+		var missing []string
+		for _, slug := range enum.Slugs {
+			if name := enum.Members[slug]; !handled[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			pass.Reportf(sw.Pos(), "missing cases in switch of type %s: %s",
+				enum.Name, strings.Join(missing, ", "))
+		}
+	})
+	return nil, nil
+}
+
+// memberName returns the name of the member matched by expr. Constant
+// expressions are resolved by value, so that the literals in the cases of
+// const-style enums (case "active") and the constants the stringer option
+// declares for int-style enums (case Active) match the member they equal.
+// Otherwise, expr must reference a package-level variable of the enum's
+// package.
+func memberName(pass *analysis.Pass, pkg *types.Package, enum *enumvalues.ValuesFact, expr ast.Expr) (string, bool) {
+	if tv, ok := pass.TypesInfo.Types[expr]; ok && tv.Value != nil {
+		switch {
+		case tv.Value.Kind() == constant.String && !enum.Int:
+			name, ok := enum.Members[constant.StringVal(tv.Value)]
+			return name, ok
+		case tv.Value.Kind() == constant.Int && enum.Int:
+			i, ok := constant.Int64Val(tv.Value)
+			if !ok || i < 0 || i >= int64(len(enum.Slugs)) {
+				return "", false
+			}
+			return enum.Members[enum.Slugs[i]], true
+		}
+		return "", false
+	}
+
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return "", false
+	}
+	if obj, ok := pass.TypesInfo.Uses[id].(*types.Var); ok && obj.Pkg() == pkg && obj.Parent() == pkg.Scope() {
+		return obj.Name(), true
+	}
+	return "", false
+}
@@ -0,0 +1,91 @@
+package a
+
+// Status has the shape of a struct-style enum.
+type Status struct {
+	slug string
+}
+
+var (
+	StatusActive   = Status{"active"}
+	StatusDisabled = Status{"disabled"}
+	StatusPending  = Status{"pending"}
+)
+
+func StatusFromString(s string) (Status, error) { return Status{s}, nil }
+
+// Shape has the shape of a const-style enum.
+type Shape string
+
+const (
+	ShapeCircle Shape = "circle"
+	ShapeSquare Shape = "square"
+	ShapeOval   Shape = "oval"
+)
+
+func ShapeFromString(s string) (Shape, error) { return Shape(s), nil }
+
+func members(s Status) {
+	switch s { // want "missing cases in switch of type Status: StatusPending"
+	case StatusActive:
+	case StatusDisabled:
+	}
+
+	switch s {
+	case StatusActive, StatusDisabled, StatusPending:
+	}
+}
+
+func literals(s Shape) {
+	switch s {
+	case "circle", "square", "oval":
+	}
+
+	switch s { // want "missing cases in switch of type Shape: ShapeOval"
+	case "circle":
+	case ShapeSquare:
+	}
+
+	switch s { // want "missing cases in switch of type Shape: ShapeSquare, ShapeOval"
+	case ("circle"), "Square":
+	}
+}
+
+// Pill has the shape of an int-style enum generated with the stringer option.
+type Pill int
+
+const (
+	PillPlacebo Pill = iota
+	PillAspirin
+	PillIbuprofen
+)
+
+const (
+	Placebo   = PillPlacebo
+	Aspirin   = PillAspirin
+	Ibuprofen = PillIbuprofen
+)
+
+const pillNames = "PlaceboAspirinIbuprofen"
+
+var pillNameIndex = [...]uint8{0, 7, 14, 23}
+
+var pillLookup = map[string]Pill{
+	"placebo":   PillPlacebo,
+	"aspirin":   PillAspirin,
+	"ibuprofen": PillIbuprofen,
+}
+
+func (e Pill) String() string { return pillNames[pillNameIndex[e]:pillNameIndex[e+1]] }
+
+func PillFromString(s string) (Pill, error) { return pillLookup[s], nil }
+
+func ints(p Pill) {
+	switch p {
+	case Placebo, PillAspirin, Ibuprofen:
+	}
+
+	switch p { // want "missing cases in switch of type Pill: PillIbuprofen"
+	case PillPlacebo:
+	case Aspirin:
+	}
+}
@@ -0,0 +1,100 @@
+// Package fromstring defines an Analyzer that reports string literals passed
+// to the constructors of generated enums that would fail to parse at run time.
+//
+// For example, given
+//
+//	// ENUM Status (active, disabled)
+//
+// the calls StatusFromString("actve") and s.Parse("typo") are reported.
+package fromstring
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"github.com/panta/go-safe-enum-generator/analysis/enumvalues"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports invalid enum string literals.
+var Analyzer = &analysis.Analyzer{
+	Name:     "enumfromstring",
+	Doc:      "report string literals that are not valid values of a generated enum",
+	Run:      run,
+	Requires: []*analysis.Analyzer{inspect.Analyzer, enumvalues.Analyzer},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	enums := pass.ResultOf[enumvalues.Analyzer].(enumvalues.Result)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if len(call.Args) != 1 {
+			return
+		}
+		fn := callee(pass, call)
+		if fn == nil {
+			return
+		}
+		enum, ok := target(enums, fn)
+		if !ok {
+			return
+		}
+		tv, ok := pass.TypesInfo.Types[call.Args[0]]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return
+		}
+		s := constant.StringVal(tv.Value)
+		if !enum.Valid(s) {
+			pass.Reportf(call.Args[0].Pos(), "invalid %s value %q, allowed values: %s",
+				enum.Name, s, strings.Join(enum.Slugs, ", "))
+		}
+	})
+	return nil, nil
+}
+
+// callee returns the function or method called by call, if statically known.
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := pass.TypesInfo.Uses[id].(*types.Func)
+	return fn
+}
+
+// target returns the enum parsed by fn: either <Name>FromString or the Parse
+// method of a generated enum.
+func target(enums enumvalues.Result, fn *types.Func) (*enumvalues.ValuesFact, bool) {
+	sig := fn.Type().(*types.Signature)
+	var t types.Type
+	switch {
+	case sig.Recv() != nil && fn.Name() == "Parse":
+		t = sig.Recv().Type()
+	case sig.Recv() == nil && strings.HasSuffix(fn.Name(), "FromString") && sig.Results().Len() > 0:
+		t = sig.Results().At(0).Type()
+	default:
+		return nil, false
+	}
+	enum, ok := enums.Lookup(t)
+	if !ok {
+		return nil, false
+	}
+	if sig.Recv() == nil && fn.Name() != enum.Name+"FromString" {
+		return nil, false
+	}
+	return enum, true
+}
@@ -0,0 +1,12 @@
+package fromstring_test
+
+import (
+	"testing"
+
+	"github.com/panta/go-safe-enum-generator/analysis/fromstring"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), fromstring.Analyzer, "a", "b")
+}
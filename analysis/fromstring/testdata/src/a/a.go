@@ -0,0 +1,60 @@
+package a
+
+// Status has the shape of a struct-style enum generated with the protojson
+// option.
+type Status struct {
+	slug string
+}
+
+var (
+	StatusActive   = Status{"active"}
+	StatusDisabled = Status{"disabled"}
+)
+
+var statusLookup = map[string]Status{
+	"active":          StatusActive,
+	"disabled":        StatusDisabled,
+	"status_active":   StatusActive,
+	"status_disabled": StatusDisabled,
+}
+
+func StatusFromString(s string) (Status, error) {
+	if v, ok := statusLookup[s]; ok {
+		return v, nil
+	}
+	return Status{}, nil
+}
+
+func (e *Status) Parse(s string) error {
+	*e, _ = StatusFromString(s)
+	return nil
+}
+
+func local() {
+	StatusFromString("active")
+	StatusFromString("actve") // want `invalid Status value "actve", allowed values: active, disabled`
+}
+
+// Pill has the shape of an int-style enum.
+type Pill int
+
+const (
+	PillPlacebo Pill = iota
+	PillAspirin
+)
+
+const pillNames = "PlaceboAspirin"
+
+var pillNameIndex = [...]uint8{0, 7, 14}
+
+var pillLookup = map[string]Pill{
+	"placebo": PillPlacebo,
+	"aspirin": PillAspirin,
+}
+
+func PillFromString(s string) (Pill, error) { return pillLookup[s], nil }
+
+func ints() {
+	PillFromString("ASPIRIN")
+	PillFromString("Ibuprofen") // want `invalid Pill value "Ibuprofen", allowed values: Placebo, Aspirin`
+}
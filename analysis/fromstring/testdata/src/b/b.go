@@ -0,0 +1,15 @@
+package b
+
+import "a"
+
+func uses() {
+	a.StatusFromString("Disabled")
+	a.StatusFromString(" active ")
+	a.StatusFromString("STATUS_ACTIVE")
+	a.StatusFromString("status_disabled")
+	a.StatusFromString("STATUS_PENDING") // want `invalid Status value "STATUS_PENDING", allowed values: active, disabled`
+
+	var s a.Status
+	s.Parse("STATUS_DISABLED")
+	s.Parse("typo") // want `invalid Status value "typo", allowed values: active, disabled`
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// diNameRegex matches the names usable in the name tags of dependency
+// injection frameworks.
+var diNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// valueAttr describes an attribute the values can declare.
+type valueAttr struct {
+	check func(string) error
+	// unique is set for the attributes identifying the values.
+	unique bool
+}
+
+// valueAttrs are the attributes a value can declare.
+var valueAttrs = map[string]valueAttr{
+	"uuid": {
+		check: func(s string) error {
+			if !uuidRegex.MatchString(s) {
+				return fmt.Errorf("invalid UUID %q", s)
+			}
+			return nil
+		},
+		unique: true,
+	},
+	"code": {
+		check: func(s string) error {
+			if s == "" {
+				return fmt.Errorf("empty code")
+			}
+			return nil
+		},
+		unique: true,
+	},
+	"topic": {
+		check: func(s string) error {
+			if s == "" {
+				return fmt.Errorf("empty topic")
+			}
+			return nil
+		},
+	},
+	"event": {
+		check: func(s string) error {
+			if s == "" {
+				return fmt.Errorf("empty event type")
+			}
+			return nil
+		},
+		unique: true,
+	},
+	"ce-type": {
+		check: func(s string) error {
+			if s == "" {
+				return fmt.Errorf("empty CloudEvents type")
+			}
+			for _, r := range s {
+				if !unicode.IsPrint(r) {
+					return fmt.Errorf("invalid CloudEvents type %q", s)
+				}
+			}
+			return nil
+		},
+		unique: true,
+	},
+	"di": {
+		check: func(s string) error {
+			if !diNameRegex.MatchString(s) {
+				return fmt.Errorf("invalid dependency injection name %q", s)
+			}
+			return nil
+		},
+		unique: true,
+	},
+	"weight": {
+		check: func(s string) error {
+			if w, err := strconv.ParseFloat(s, 64); err != nil || math.IsInf(w, 0) || math.IsNaN(w) {
+				return fmt.Errorf("invalid weight %q", s)
+			}
+			return nil
+		},
+	},
+}
+
+// checkValueAttrs checks the attributes of the values of enum: either all the
+// values declare an attribute or none does, and no two values declare the same
+// identifying one. It also checks that the hashes of the values, if generated,
+// are unique.
+func checkValueAttrs(enum enumDef) error {
+	declared := map[string]int{}
+	seen := map[string]map[string]string{}
+	for _, v := range enum.Values {
+		for key, value := range v.Attrs {
+			attr, ok := valueAttrs[key]
+			if !ok {
+				return fmt.Errorf("value %q: unknown attribute %q", v.Original, key)
+			}
+			if err := attr.check(value); err != nil {
+				return fmt.Errorf("value %q: %w", v.Original, err)
+			}
+			declared[key]++
+			if !attr.unique {
+				continue
+			}
+			if key == "uuid" {
+				value = strings.ToLower(value)
+			}
+			if seen[key] == nil {
+				seen[key] = map[string]string{}
+			}
+			if other, ok := seen[key][value]; ok {
+				return fmt.Errorf("values %q and %q have the same %s", other, v.Original, key)
+			}
+			seen[key][value] = v.Original
+		}
+	}
+	keys := make([]string, 0, len(declared))
+	for key := range declared {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if declared[key] < len(enum.Values) {
+			return fmt.Errorf("either all the values or none must declare a %s", key)
+		}
+	}
+
+	if enum.Hash {
+		hashes := map[uint32]string{}
+		for _, v := range enum.Values {
+			h := enum.HashOf(v)
+			if other, ok := hashes[h]; ok {
+				return fmt.Errorf("values %q and %q have the same hash", other, v.Original)
+			}
+			hashes[h] = v.Original
+		}
+	}
+	return nil
+}
+
+// HasUUIDs reports whether the values of the enum declare UUIDs.
+func (e enumDef) HasUUIDs() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["uuid"] != ""
+}
+
+// HasCodes reports whether the values of the enum declare short codes.
+func (e enumDef) HasCodes() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["code"] != ""
+}
+
+// HasTopics reports whether the values of the enum declare the topics their
+// events are published to.
+func (e enumDef) HasTopics() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["topic"] != ""
+}
+
+// Topics returns the distinct topics declared by the values, in order of
+// declaration.
+func (e enumDef) Topics() []string {
+	var topics []string
+	seen := map[string]bool{}
+	for _, v := range e.Values {
+		if topic := v.Attrs["topic"]; !seen[topic] {
+			topics = append(topics, topic)
+			seen[topic] = true
+		}
+	}
+	return topics
+}
+
+// HasEventTypes reports whether the values of the enum declare event types.
+func (e enumDef) HasEventTypes() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["event"] != ""
+}
+
+// HasCloudEventTypes reports whether the values of the enum declare
+// CloudEvents types.
+func (e enumDef) HasCloudEventTypes() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["ce-type"] != ""
+}
+
+// HasDINames reports whether the values of the enum declare the names of
+// their implementations for dependency injection.
+func (e enumDef) HasDINames() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["di"] != ""
+}
+
+// HasWeights reports whether the values of the enum declare weights.
+func (e enumDef) HasWeights() bool {
+	return len(e.Values) > 0 && e.Values[0].Attrs["weight"] != ""
+}
+
+// WeightOf returns the weight declared for v, or 0.
+func (e enumDef) WeightOf(v valueInfo) float64 {
+	w, _ := strconv.ParseFloat(v.Attrs["weight"], 64)
+	return w
+}
+
+// HashOf returns the stable identifier of v: the 32-bit FNV-1a hash of its
+// UUID (in lower case) if declared, otherwise of its string.
+func (e enumDef) HashOf(v valueInfo) uint32 {
+	key := v.Original
+	if uuid := v.Attrs["uuid"]; uuid != "" {
+		key = strings.ToLower(uuid)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// avroNameRegex matches the names allowed by Avro for symbols and namespace parts.
+var avroNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// avroSchema is the Avro enum schema of an enum.
+type avroSchema struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Symbols   []string `json:"symbols"`
+}
+
+// writeAvroSchemas writes the Avro schema of each enum to dir, as <Name>.avsc.
+func writeAvroSchemas(dir string, enums []enumDef) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating Avro schema directory: %w", err)
+	}
+	for _, enum := range enums {
+		data, err := avroJSON(enum)
+		if err != nil {
+			return err
+		}
+		if err := writeOutput(filepath.Join(dir, enum.Name+".avsc"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// avroJSON returns the Avro schema of enum. The namespace is the avro-namespace
+// option if set, the package name otherwise. Values that aren't valid Avro
+// symbols are rejected, since they couldn't be exchanged unchanged.
+func avroJSON(enum enumDef) ([]byte, error) {
+	schema := avroSchema{
+		Type:      "enum",
+		Name:      enum.Name,
+		Namespace: enum.AvroNamespace,
+		Symbols:   make([]string, 0, len(enum.Values)),
+	}
+	if schema.Namespace == "" {
+		schema.Namespace = enum.Package
+	}
+	for _, part := range strings.Split(schema.Namespace, ".") {
+		if !avroNameRegex.MatchString(part) {
+			return nil, fmt.Errorf("invalid Avro namespace %q for %s", schema.Namespace, enum.Name)
+		}
+	}
+	for _, v := range enum.Values {
+		if !avroNameRegex.MatchString(v.Original) {
+			return nil, fmt.Errorf("value %q of %s is not a valid Avro symbol", v.Original, enum.Name)
+		}
+		schema.Symbols = append(schema.Symbols, v.Original)
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding Avro schema of %s: %w", enum.Name, err)
+	}
+	return append(data, '\n'), nil
+}
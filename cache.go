@@ -0,0 +1,153 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+// generationCache records the generations of the input files in a directory,
+// so that the unchanged ones can be skipped.
+type generationCache struct {
+	dir string
+}
+
+// cacheEntry is the record of the last generation of an input file: the key
+// of its parsed directives and options, and the hashes of the files written.
+type cacheEntry struct {
+	Key     string            `json:"key"`
+	Outputs map[string]string `json:"outputs"`
+}
+
+// openCache returns the cache stored in dir, or in the user cache directory if
+// empty.
+func openCache(dir string) (*generationCache, error) {
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("locating the cache: %w", err)
+		}
+		dir = filepath.Join(userDir, "go-safe-enum-generator")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating the cache: %w", err)
+	}
+	return &generationCache{dir: dir}, nil
+}
+
+// generationKey returns the hash of everything the generation of def into
+// output depends on: the generator build, the parsed directives (with the
+// translations and previous order applied), the options and the custom
+// template.
+func generationKey(def fileDef, output string, opts genOptions) (string, error) {
+	h := sha256.New()
+	fmt.Fprintln(h, generatorVersion())
+	data, err := json.Marshal(struct {
+		Def    fileDef
+		Output string
+		Opts   genOptions
+	}{def, output, opts})
+	if err != nil {
+		return "", fmt.Errorf("hashing the directives: %w", err)
+	}
+	h.Write(data)
+	if opts.Template != "" {
+		tmpl, err := os.ReadFile(opts.Template)
+		if err != nil {
+			return "", err
+		}
+		h.Write(tmpl)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// generatorVersion identifies the build of the generator, so that upgrading it
+// invalidates the cache: its module version and VCS revision, or the size and
+// modification time of the executable for the other builds.
+func generatorVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version := info.Main.Version
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+				version += " " + s.Value
+			}
+		}
+		if version != "(devel)" && version != "" {
+			return version
+		}
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "unknown"
+	}
+	fi, err := os.Stat(exe)
+	if err != nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d %d", fi.Size(), fi.ModTime().UnixNano())
+}
+
+// entryPath returns the file recording the generation of filename into output.
+func (c *generationCache) entryPath(filename, output string) string {
+	abs := func(path string) string {
+		if a, err := filepath.Abs(path); err == nil {
+			return a
+		}
+		return path
+	}
+	sum := sha256.Sum256([]byte(abs(filename) + "\x00" + abs(output)))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// fresh reports whether filename was last generated into output with key, and
+// the files written weren't modified or removed since.
+func (c *generationCache) fresh(filename, output, key string) bool {
+	data, err := os.ReadFile(c.entryPath(filename, output))
+	if err != nil {
+		return false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key || len(entry.Outputs) == 0 {
+		return false
+	}
+	for path, hash := range entry.Outputs {
+		if current := fileHash(path); current == "" || current != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// store records the generation of filename into output with key, and the
+// hashes of output and of the other files written, among those given that
+// exist.
+func (c *generationCache) store(filename, output, key string, also ...string) error {
+	entry := cacheEntry{Key: key, Outputs: map[string]string{}}
+	for _, path := range append([]string{output}, also...) {
+		if hash := fileHash(path); hash != "" {
+			entry.Outputs[path] = hash
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.entryPath(filename, output), data, 0o644); err != nil {
+		return fmt.Errorf("writing the cache: %w", err)
+	}
+	return nil
+}
+
+// fileHash returns the hash of the content of path, or "" if it can't be read.
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
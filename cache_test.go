@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheFresh(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := openCache(filepath.Join(dir, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, output := filepath.Join(dir, "types.go"), filepath.Join(dir, "types_gen.go")
+	schema, models := filepath.Join(dir, "schema.graphql"), filepath.Join(dir, "schema.gqlgen.yml")
+	for _, path := range []string{input, output, schema} {
+		if err := os.WriteFile(path, []byte(path), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the gqlgen models weren't written, and aren't recorded
+	if err := cache.store(input, output, "key", schema, models); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.fresh(input, output, "key") {
+		t.Fatal("generation not fresh after store")
+	}
+	if cache.fresh(input, output, "other key") {
+		t.Error("generation fresh with another key")
+	}
+
+	if err := os.WriteFile(schema, []byte("modified"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cache.fresh(input, output, "key") {
+		t.Error("generation fresh with a modified output")
+	}
+	if err := os.Remove(schema); err != nil {
+		t.Fatal(err)
+	}
+	if cache.fresh(input, output, "key") {
+		t.Error("generation fresh with a removed output")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// writeChangelog writes to output a Markdown list of the changes to the enums
+// of def since the against git revision of filename, meant to be pasted into
+// release notes. The list is empty when nothing changed. The previous version
+// is parsed with opts, like def.
+func writeChangelog(output, filename, against string, def fileDef, opts genOptions) error {
+	previous, err := loadPrevious(filename, against, opts)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, c := range compareEnums(previous, def) {
+		fmt.Fprintf(&buf, "- %s\n", changelogEntry(c))
+	}
+	return writeOutput(output, buf.Bytes())
+}
+
+// changelogEntry describes c for humans.
+func changelogEntry(c enumChange) string {
+	switch c.Kind {
+	case enumAdded:
+		return fmt.Sprintf("Added enum `%s`", c.Enum)
+	case enumRemoved:
+		return fmt.Sprintf("Removed enum `%s` (breaking)", c.Enum)
+	case valueAdded:
+		return fmt.Sprintf("`%s`: added `%s`", c.Enum, c.Value)
+	case valueRemoved:
+		return fmt.Sprintf("`%s`: removed `%s` (breaking)", c.Enum, c.Value)
+	case valueRenamed:
+		return fmt.Sprintf("`%s`: renamed `%s` to `%s` (breaking)", c.Enum, c.Value, c.NewValue)
+	default:
+		return fmt.Sprintf("`%s`: moved `%s` from %d to %d (breaking)", c.Enum, c.Value, c.Int, c.NewInt)
+	}
+}
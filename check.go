@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// errOutdated reports in --check mode that an output file doesn't hold the
+// code generated from its input.
+var errOutdated = errors.New("out of date")
+
+// checkOutput returns an error wrapping errOutdated if output doesn't hold code.
+func checkOutput(output string, code []byte) error {
+	if output == "" {
+		return errors.New("--check requires an output file")
+	}
+	current, err := os.ReadFile(output)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading output: %w", err)
+	}
+	if !bytes.Equal(current, code) {
+		return fmt.Errorf("%s is %w (run the generator again)", output, errOutdated)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// clickhouseType returns the ClickHouse Enum8 (or, for more than 128 values,
+// Enum16) column type of values, numbered after their int mapping.
+func clickhouseType(values []valueInfo) (string, error) {
+	typ := "Enum8"
+	if len(values) > math.MaxInt8+1 {
+		typ = "Enum16"
+	}
+	if len(values) > math.MaxInt16+1 {
+		return "", fmt.Errorf("too many values for a ClickHouse enum (%d)", len(values))
+	}
+
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = fmt.Sprintf("'%s' = %d", quote.Replace(v.Original), i)
+	}
+	return typ + "(" + strings.Join(items, ", ") + ")", nil
+}
@@ -0,0 +1,23 @@
+// Command safe-enum-vet runs the go-safe-enum-generator analyzers.
+//
+// It can be used standalone:
+//
+//	safe-enum-vet ./...
+//
+// or as a vet tool:
+//
+//	go vet -vettool=$(which safe-enum-vet) ./...
+package main
+
+import (
+	"github.com/panta/go-safe-enum-generator/analysis/exhaustive"
+	"github.com/panta/go-safe-enum-generator/analysis/fromstring"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(
+		exhaustive.Analyzer,
+		fromstring.Analyzer,
+	)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// checkCollisions reports the package-level identifiers declared twice in the
+// generated code, or also declared by the Go files of the package in dir (if
+// not empty) other than the outputs, which would make the package fail to
+// compile.
+func checkCollisions(pkg, dir string, outputs []string, generated ...[]byte) error {
+	declared := map[string]bool{}
+	for _, code := range generated {
+		if code == nil {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+		if err != nil {
+			return fmt.Errorf("parsing generated code: %w", err)
+		}
+		for _, name := range packageNames(f) {
+			switch {
+			case declared[name] && !token.IsExported(name):
+				return fmt.Errorf("%s is generated twice, for enums whose names only differ by case (use --var-names=namespaced)", name)
+			case declared[name]:
+				return fmt.Errorf("%s is generated twice", name)
+			}
+			declared[name] = true
+		}
+	}
+	if dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	isOutput := map[string]bool{}
+	for _, output := range outputs {
+		isOutput[filepath.Clean(output)] = true
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || isOutput[filepath.Clean(file)] {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+		if err != nil || f.Name.Name != pkg {
+			continue
+		}
+		for _, name := range packageNames(f) {
+			if declared[name] {
+				return fmt.Errorf("generated %s collides with the one declared in %s (rename it, or use --var-names=namespaced or the skip option)", name, file)
+			}
+		}
+	}
+	return nil
+}
+
+// packageNames returns the package-level identifiers declared by f, but the
+// methods, the blank identifier and init.
+func packageNames(f *ast.File) []string {
+	var names []string
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil && decl.Name.Name != "init" {
+				names = append(names, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = append(names, spec.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.Name != "_" {
+							names = append(names, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// compatBreakingExitCode is the exit code of the compat command when breaking
+// changes are found (1 being used for the other errors).
+const compatBreakingExitCode = 2
+
+// compatCmd compares the enums declared in a file to a previous version.
+type compatCmd struct {
+	File    string `help:"Input file to check" short:"f" required:""`
+	Against string `help:"Previous version: a file, or a git ref to read the input file at" required:""`
+
+	AppendOnly  bool   `help:"Compare the ints the members get when generated with --append-only, following the order of the previous version"`
+	PackageName string `help:"Package of the input file, when generated with --package-name (the file then doesn't need to be valid Go)"`
+}
+
+type changeKind int
+
+const (
+	enumAdded changeKind = iota
+	enumRemoved
+	valueAdded
+	valueRemoved
+	valueRenamed
+	valueRenumbered
+)
+
+// enumChange is a difference between two versions of the enums of a file.
+type enumChange struct {
+	Kind  changeKind
+	Enum  string
+	Value string
+	// NewValue is the new string of a renamed value.
+	NewValue string
+	// Int and NewInt are the old and new ints of a renumbered value.
+	Int, NewInt int
+}
+
+// Breaking reports whether the change breaks the wire compatibility.
+func (c enumChange) Breaking() bool {
+	return c.Kind != enumAdded && c.Kind != valueAdded
+}
+
+func (c enumChange) String() string {
+	switch c.Kind {
+	case enumAdded:
+		return fmt.Sprintf("%s: enum added", c.Enum)
+	case enumRemoved:
+		return fmt.Sprintf("%s: enum removed", c.Enum)
+	case valueAdded:
+		return fmt.Sprintf("%s: value %q added", c.Enum, c.Value)
+	case valueRemoved:
+		return fmt.Sprintf("%s: value %q removed", c.Enum, c.Value)
+	case valueRenamed:
+		return fmt.Sprintf("%s: value %q changed to %q", c.Enum, c.Value, c.NewValue)
+	default:
+		return fmt.Sprintf("%s: int of %q changed from %d to %d", c.Enum, c.Value, c.Int, c.NewInt)
+	}
+}
+
+// Run prints the changes and exits with compatBreakingExitCode if any of them
+// breaks the wire compatibility.
+func (c *compatCmd) Run(ctx *kong.Context) error {
+	opts := defaultGenOptions()
+	opts.PackageName = c.PackageName
+	current, err := loadFile(c.File, opts)
+	if err != nil {
+		return err
+	}
+	previous, err := loadPrevious(c.File, c.Against, opts)
+	if err != nil {
+		return err
+	}
+	if c.AppendOnly {
+		current = keepOrderOf(previous, current)
+	}
+
+	breaking := false
+	for _, change := range compareEnums(previous, current) {
+		kind := "compatible"
+		if change.Breaking() {
+			kind, breaking = "breaking", true
+		}
+		fmt.Fprintf(ctx.Stdout, "%s: %s\n", kind, change)
+	}
+	if breaking {
+		ctx.Exit(compatBreakingExitCode)
+	}
+	return nil
+}
+
+// loadPrevious loads the enums of against if it's a regular file, or else of
+// filename at the against git revision.
+func loadPrevious(filename, against string, opts genOptions) (fileDef, error) {
+	if fi, err := os.Stat(against); err == nil && fi.Mode().IsRegular() {
+		return loadFile(against, opts)
+	}
+
+	cmd := exec.Command("git", "show", against+":./"+filepath.Base(filename))
+	cmd.Dir = filepath.Dir(filename)
+	data, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fileDef{}, fmt.Errorf("reading %s at %s: %s", filename, against, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fileDef{}, fmt.Errorf("reading %s at %s: %w", filename, against, err)
+	}
+
+	tmp, err := os.CreateTemp("", "enum-compat-*.go")
+	if err != nil {
+		return fileDef{}, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fileDef{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return fileDef{}, err
+	}
+	return loadFile(tmp.Name(), opts)
+}
+
+// keepOrderOf reorders the values of the current enums as append-only mode
+// does: those of the previous version (matched by member name) keep their
+// order, and the new ones follow in declaration order.
+func keepOrderOf(previous, current fileDef) fileDef {
+	enums := make([]enumDef, len(current.Enums))
+	for i, cur := range current.Enums {
+		enums[i] = cur
+		old := findEnum(previous.Enums, cur.Name)
+		if old == nil {
+			continue
+		}
+		values := make([]valueInfo, 0, len(cur.Values))
+		kept := map[string]bool{}
+		for _, v := range old.Values {
+			if same, ok := findGoName(cur.Values, v.GoName); ok {
+				values = append(values, same)
+				kept[strings.Title(v.GoName)] = true
+			}
+		}
+		for _, v := range cur.Values {
+			if !kept[strings.Title(v.GoName)] {
+				values = append(values, v)
+			}
+		}
+		enums[i].Values = values
+	}
+	current.Enums = enums
+	return current
+}
+
+// compareEnums returns the changes from the previous to the current enums.
+// Removing an enum or a value, changing the string of a member (found by its
+// Go name) or the int a value maps to break the compatibility; adding enums
+// or values at the end doesn't.
+func compareEnums(previous, current fileDef) []enumChange {
+	var changes []enumChange
+	for _, old := range previous.Enums {
+		cur := findEnum(current.Enums, old.Name)
+		if cur == nil {
+			changes = append(changes, enumChange{Kind: enumRemoved, Enum: old.Name})
+			continue
+		}
+
+		for i, v := range old.Values {
+			j := valueIndex(cur.Values, v.Original)
+			switch {
+			case j >= 0 && j != i:
+				changes = append(changes, enumChange{Kind: valueRenumbered, Enum: old.Name, Value: v.Original, Int: i, NewInt: j})
+			case j < 0:
+				if renamed, ok := findGoName(cur.Values, v.GoName); ok {
+					changes = append(changes, enumChange{Kind: valueRenamed, Enum: old.Name, Value: v.Original, NewValue: renamed.Original})
+				} else {
+					changes = append(changes, enumChange{Kind: valueRemoved, Enum: old.Name, Value: v.Original})
+				}
+			}
+		}
+		for _, v := range cur.Values {
+			_, renamed := findGoName(old.Values, v.GoName)
+			if valueIndex(old.Values, v.Original) < 0 && !renamed {
+				changes = append(changes, enumChange{Kind: valueAdded, Enum: old.Name, Value: v.Original})
+			}
+		}
+	}
+	for _, cur := range current.Enums {
+		if findEnum(previous.Enums, cur.Name) == nil {
+			changes = append(changes, enumChange{Kind: enumAdded, Enum: cur.Name})
+		}
+	}
+	return changes
+}
+
+// valueIndex returns the index of the value with the given string, or -1.
+func valueIndex(values []valueInfo, original string) int {
+	for i, v := range values {
+		if v.Original == original {
+			return i
+		}
+	}
+	return -1
+}
+
+// findGoName returns the value with the given Go name, compared as in the
+// member identifiers (title cased).
+func findGoName(values []valueInfo, goName string) (valueInfo, bool) {
+	for _, v := range values {
+		if strings.Title(v.GoName) == strings.Title(goName) {
+			return v, true
+		}
+	}
+	return valueInfo{}, false
+}
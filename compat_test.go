@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompatAppendOnly(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, values string) string {
+		path := filepath.Join(dir, name)
+		// not valid Go, as with --package-name
+		if err := os.WriteFile(path, []byte("// ENUM Color ("+values+")\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	previousFile := write("previous.enums", "red, green, blue")
+	currentFile := write("current.enums", "blue, red, green, pink")
+
+	opts := defaultGenOptions()
+	opts.PackageName = "p"
+	current, err := loadFile(currentFile, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := loadPrevious(currentFile, previousFile, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []changeKind
+	for _, c := range compareEnums(previous, current) {
+		kinds = append(kinds, c.Kind)
+	}
+	if want := []changeKind{valueRenumbered, valueRenumbered, valueRenumbered, valueAdded}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("changes = %v, want %v", kinds, want)
+	}
+	changes := compareEnums(previous, keepOrderOf(previous, current))
+	if want := []enumChange{{Kind: valueAdded, Enum: "Color", Value: "pink"}}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("append-only changes = %v, want %v", changes, want)
+	}
+}
+
+func TestLoadPreviousDirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	file := filepath.Join(dir, "types.go")
+	if err := os.WriteFile(file, []byte("package p\n\n// ENUM Color (red)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q", "-b", "main")
+	git("add", "types.go")
+	git("commit", "-q", "-m", "types")
+
+	// a directory named like the revision doesn't hide it
+	if err := os.Mkdir(filepath.Join(dir, "main"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	previous, err := loadPrevious(file, "main", defaultGenOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(previous.Enums) != 1 || previous.Enums[0].Name != "Color" {
+		t.Errorf("previous enums = %+v", previous.Enums)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// parseCustomTemplate parses the user template at path, with the functions
+// of the built-in templates.
+func parseCustomTemplate(path string) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderCustomTemplate executes tmpl with def (see the template-schema
+// command), formatting the result when output is a Go file.
+func renderCustomTemplate(tmpl *template.Template, output string, def fileDef) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, def); err != nil {
+		return nil, fmt.Errorf("executing template: %w", err)
+	}
+	code := normalizeNewlines(buf.Bytes())
+	if !strings.HasSuffix(output, ".go") {
+		return code, nil
+	}
+	formatted, err := format.Source(code)
+	if err != nil {
+		return nil, fmt.Errorf("formatting %s: %w", output, err)
+	}
+	return formatted, nil
+}
+
+// writeCustomTemplate renders the user template at path for def into output.
+func writeCustomTemplate(path, output string, def fileDef) error {
+	tmpl, err := parseCustomTemplate(path)
+	if err != nil {
+		return err
+	}
+	code, err := renderCustomTemplate(tmpl, output, def)
+	if err != nil {
+		return err
+	}
+	return writeOutput(output, code)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// moduleRequirement is a module the generated code may import, at the
+// oldest version it is known to work with.
+type moduleRequirement struct {
+	Path    string
+	Version string
+}
+
+// moduleRequirements are the modules of the third-party packages the
+// generated code may import.
+var moduleRequirements = []moduleRequirement{
+	{"github.com/Masterminds/squirrel", "v1.5.4"},
+	{"github.com/caarlos0/env/v11", "v11.3.1"},
+	{"github.com/danielgtaylor/huma/v2", "v2.27.0"},
+	{"github.com/doug-martin/goqu/v9", "v9.19.0"},
+	{"github.com/hashicorp/terraform-plugin-framework", "v1.13.0"},
+	{"github.com/hashicorp/terraform-plugin-framework-validators", "v0.16.0"},
+	{"github.com/prometheus/client_golang", "v1.20.5"},
+	{"go.mongodb.org/mongo-driver/v2", "v2.0.0"},
+	{"go.opentelemetry.io/otel", "v1.34.0"},
+	{"gopkg.in/yaml.v3", "v3.0.1"},
+}
+
+// externalImports returns the sorted import paths of the generated code that
+// aren't in the standard library.
+func externalImports(generated ...[]byte) ([]string, error) {
+	seen := map[string]bool{}
+	var deps []string
+	for _, code := range generated {
+		if code == nil {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), "", code, parser.ImportsOnly)
+		if err != nil {
+			return nil, fmt.Errorf("parsing generated code: %w", err)
+		}
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || seen[path] || isStdlib(path) {
+				continue
+			}
+			seen[path] = true
+			deps = append(deps, path)
+		}
+	}
+	sort.Strings(deps)
+	return deps, nil
+}
+
+// listDeps writes to w the import paths of the generated code that aren't in
+// the standard library, one per line, so that build systems can declare them
+// as dependencies of the generated files.
+func listDeps(w io.Writer, generated ...[]byte) error {
+	deps, err := externalImports(generated...)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		if _, err := fmt.Fprintln(w, dep); err != nil {
+			return &writeError{fmt.Errorf("writing dependencies: %w", err)}
+		}
+	}
+	return nil
+}
+
+// requiredModules returns the modules providing the third-party packages
+// imported by the generated code.
+func requiredModules(generated ...[]byte) ([]moduleRequirement, error) {
+	deps, err := externalImports(generated...)
+	if err != nil {
+		return nil, err
+	}
+	var required []moduleRequirement
+	for _, mod := range moduleRequirements {
+		for _, dep := range deps {
+			if dep == mod.Path || strings.HasPrefix(dep, mod.Path+"/") {
+				required = append(required, mod)
+				break
+			}
+		}
+	}
+	return required, nil
+}
+
+// writeRequirements writes to output the require block of go.mod listing the
+// modules needed by the generated code.
+func writeRequirements(output string, required []moduleRequirement) error {
+	var buf bytes.Buffer
+	buf.WriteString(generatedHeader("go-safe-enum-generator") + "\n")
+	buf.WriteString("// The modules required by the generated enums, to merge into go.mod.\n\n")
+	buf.WriteString("require (\n")
+	for _, mod := range required {
+		fmt.Fprintf(&buf, "\t%s %s\n", mod.Path, mod.Version)
+	}
+	buf.WriteString(")\n")
+	return writeOutput(output, buf.Bytes())
+}
+
+// warnMissingModules logs the required modules missing from the go.mod file
+// of the module containing dir, with the command adding them.
+func warnMissingModules(dir string, required []moduleRequirement) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			for _, mod := range required {
+				if !regexp.MustCompile(`(?m)^\s*(require\s+)?` + regexp.QuoteMeta(mod.Path) + `\s+v`).Match(data) {
+					logger.Warn("the generated code needs a module missing from go.mod", "module", mod.Path, "fix", "go get "+mod.Path+"@"+mod.Version)
+				}
+			}
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+// isStdlib reports whether path is the import path of a standard library
+// package, whose first element has no dot.
+func isStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// diagnostic is an error located in an input file.
+type diagnostic struct {
+	File string `json:"file"`
+	// Line is 0 for the errors concerning the whole file.
+	Line     int    `json:"line"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+func errorAt(filename string, line int, code, format string, args ...interface{}) *diagnostic {
+	return &diagnostic{
+		File:     filename,
+		Line:     line,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: "error",
+	}
+}
+
+func (d *diagnostic) Error() string {
+	if d.Line == 0 {
+		return d.Message
+	}
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+}
+
+// writeJSONDiagnostic writes err to w as a JSON diagnostic on a single line.
+// Errors not located by the parser are reported for filename, with the
+// "error" code.
+func writeJSONDiagnostic(w io.Writer, filename string, err error) error {
+	var d *diagnostic
+	if !errors.As(err, &d) {
+		d = errorAt(filename, 0, "error", "%s", err.Error())
+	}
+	return json.NewEncoder(w).Encode(d)
+}
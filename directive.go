@@ -0,0 +1,626 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// enumRegex matches the first line of an ENUM directive, optionally
+	// extending or restricting another enum. The value list may continue on
+	// the following comment lines until the closing parenthesis.
+	enumRegex = regexp.MustCompile(`^\s*//\s*ENUM\s+(\w+)(?:\s+(extends|subset-of)\s+(\w+))?\s*\((.*)$`)
+	// continuationRegex matches the comment lines following an unterminated directive.
+	continuationRegex = regexp.MustCompile(`^\s*//(.*)$`)
+	// trailingCommentRegex matches a "# comment" documenting the last value of a line.
+	trailingCommentRegex = regexp.MustCompile(`(^|\s)#\s*(.*)$`)
+	// namespaceRegex matches a namespace directive, applying its options to
+	// the enums that follow until the next namespace directive or "end".
+	namespaceRegex = regexp.MustCompile(`^\s*//\s*ENUM-NAMESPACE\s+(\w+)(.*)$`)
+	// mappingRegex matches the first line of a mapping between two enums.
+	mappingRegex = regexp.MustCompile(`^\s*//\s*ENUM-MAP\s+(\w+)\s+(\w+)\s*\((.*)$`)
+	// directiveLikeRegex matches the comments looking like directives, to
+	// report the ones that don't parse.
+	directiveLikeRegex = regexp.MustCompile(`^\s*//\s*ENUM(-MAP|-NAMESPACE)?\s`)
+)
+
+// parseFile returns the enums and mappings declared by the directives in filename.
+//
+// A directive lists its values on a single line or across several comment
+// lines; text following a " #" on a line documents the last value of that line:
+//
+//	// ENUM Status (
+//	//   active,   # the account can be used
+//	//   disabled  # the account was blocked by an admin
+//	// )
+//
+// Values holding commas, parentheses or # are quoted: "a, b" or 'a, b'.
+//
+// Options following the closing parenthesis override the command line ones
+// for that enum, and a namespace directive applies shared options to a group
+// of enums:
+//
+//	// ENUM-NAMESPACE billing style=const yaml
+//	// ENUM Plan (free, pro)
+//	// ENUM Interval (month, year) no-yaml
+//	// ENUM-NAMESPACE end
+//
+// An enum can extend another one declared before it in the same file, adding
+// values after the base ones (a leading "+" on the added values is optional):
+//
+//	// ENUM ExtendedStatus extends Status (+archived)
+//
+// or declare a subset of the values of another enum:
+//
+//	// ENUM ActiveStatus subset-of Status (active, pending)
+//
+// Values can be followed by attributes in brackets:
+//
+//	// ENUM Plan (free [uuid=0b7f0c4e-6c2f-4f57-9d3e-2f1f3d7b1a10], pro [uuid=...])
+//
+// Finally, a mapping directive declares the correspondence between the values
+// of two enums declared before it:
+//
+//	// ENUM-MAP Status APIStatus (active=ACTIVE, disabled=INACTIVE)
+func parseFile(filename, pkgName string, opts genOptions) (fileDef, error) {
+	def := fileDef{Package: pkgName}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return def, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := newLineScanner(file, opts.MaxLineLen)
+
+	var enums []enumDef
+	namespace, nsOpts := "", opts
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if ns := namespaceRegex.FindStringSubmatch(scanner.Text()); ns != nil {
+			namespace, nsOpts = "", opts
+			if ns[1] != "end" {
+				namespace = ns[1]
+				if err := parseOptions(&nsOpts, ns[2]); err != nil {
+					return def, errorAt(filename, lineNo, "invalid-option", "namespace %s: %v", namespace, err)
+				}
+			}
+			continue
+		}
+
+		if m := mappingRegex.FindStringSubmatch(scanner.Text()); m != nil {
+			startLine := lineNo
+			pairs, _, err := readValues(scanner, m[3], &lineNo)
+			if scanner.Err() != nil {
+				break
+			}
+			if errors.Is(err, errUnterminated) {
+				return def, errorAt(filename, startLine, "unterminated-directive", "unterminated ENUM-MAP directive %s %s", m[1], m[2])
+			}
+			if err != nil {
+				return def, errorAt(filename, startLine, "invalid-mapping", "ENUM-MAP %s %s: %v", m[1], m[2], err)
+			}
+			mapping, err := newMapping(enums, m[1], m[2], pairs)
+			if err != nil {
+				return def, errorAt(filename, startLine, "invalid-mapping", "ENUM-MAP %s %s: %v", m[1], m[2], err)
+			}
+			def.Mappings = append(def.Mappings, mapping)
+			continue
+		}
+
+		matches := enumRegex.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			if directiveLikeRegex.MatchString(scanner.Text()) {
+				logger.Info("ignoring malformed directive", "file", filename, "line", lineNo, "text", strings.TrimSpace(scanner.Text()))
+			}
+			continue
+		}
+
+		name, relation, baseName := matches[1], matches[2], matches[3]
+		startLine := lineNo
+		values, options, err := readValues(scanner, matches[4], &lineNo)
+		if scanner.Err() != nil {
+			break
+		}
+		if errors.Is(err, errUnterminated) {
+			return def, errorAt(filename, startLine, "unterminated-directive", "unterminated ENUM directive %s", name)
+		}
+		if err != nil {
+			return def, errorAt(filename, startLine, "invalid-enum", "ENUM %s: %v", name, err)
+		}
+
+		enumOpts := nsOpts
+		if err := parseOptions(&enumOpts, options); err != nil {
+			return def, errorAt(filename, startLine, "invalid-option", "ENUM %s: %v", name, err)
+		}
+		enum := newEnumDef(pkgName, namespace, name, values, enumOpts)
+		switch relation {
+		case "extends":
+			err = extendEnum(&enum, enums, baseName)
+		case "subset-of":
+			err = restrictEnum(&enum, enums, baseName)
+		}
+		if err == nil && len(enum.Values) == 0 {
+			err = errors.New("no values declared")
+		}
+		if err == nil {
+			err = checkValueAttrs(enum)
+		}
+		if err == nil && enum.Set && len(enum.Values) > 64 {
+			err = fmt.Errorf("sets are limited to 64 values, %s has %d", name, len(enum.Values))
+		}
+		if err == nil && enum.Fuzzy {
+			err = checkFuzzyKeys(enum)
+		}
+		if err == nil && enum.Stringer {
+			err = checkStringerNames(enum)
+		}
+		if err != nil {
+			return def, errorAt(filename, startLine, "invalid-enum", "ENUM %s: %v", name, err)
+		}
+		if len(enum.Values) == 1 {
+			logger.Warn("enum with a single value", "file", filename, "line", startLine, "enum", name)
+		}
+		logger.Debug("found enum", "file", filename, "line", startLine, "enum", name, "values", len(enum.Values), "options", enabledFeatures(enum))
+		enums = append(enums, enum)
+	}
+
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return def, errorAt(filename, lineNo+1, "line-too-long", "line longer than %d bytes (split the directive over several lines, or raise --max-line-length)", maxLineLen(opts.MaxLineLen))
+	} else if err != nil {
+		return def, fmt.Errorf("scanning file: %w", err)
+	}
+
+	if len(enums) == 0 {
+		return def, errorAt(filename, 0, "no-enums", "no enum definitions found in %s", filename)
+	}
+	def.Enums = enums
+	return def, nil
+}
+
+// defaultMaxLineLen is the maximum length of the lines of the input files when
+// not configured, long enough for directives listing thousands of values.
+const defaultMaxLineLen = 1 << 20
+
+// maxLineLen returns the configured maximum line length, or the default one.
+func maxLineLen(configured int) int {
+	if configured <= 0 {
+		return defaultMaxLineLen
+	}
+	return configured
+}
+
+// newLineScanner returns a scanner of the lines of r, failing with
+// bufio.ErrTooLong on the lines longer than max bytes (defaultMaxLineLen if
+// not positive).
+func newLineScanner(r io.Reader, max int) *bufio.Scanner {
+	max = maxLineLen(max)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(max, bufio.MaxScanTokenSize)), max)
+	return scanner
+}
+
+// errUnterminated is returned by readValues for the directives missing their
+// closing parenthesis.
+var errUnterminated = errors.New("unterminated directive")
+
+// readValues parses the values of a directive, starting with the rest of its
+// first line and consuming continuation lines from scanner until the closing
+// parenthesis. It returns the values and the options following the
+// parenthesis, or errUnterminated if the directive isn't terminated.
+func readValues(scanner *bufio.Scanner, rest string, lineNo *int) ([]valueInfo, string, error) {
+	values := make([]valueInfo, 0)
+	start := *lineNo
+	for {
+		var closed bool
+		var options string
+		var err error
+		if values, closed, options, err = parseValuesLine(values, rest); err != nil && *lineNo != start {
+			return nil, "", fmt.Errorf("line %d: %w", *lineNo, err)
+		} else if err != nil {
+			return nil, "", err
+		}
+		if closed {
+			return values, options, nil
+		}
+		if !scanner.Scan() {
+			return nil, "", errUnterminated
+		}
+		*lineNo++
+		cont := continuationRegex.FindStringSubmatch(scanner.Text())
+		if cont == nil {
+			return nil, "", errUnterminated
+		}
+		rest = cont[1]
+	}
+}
+
+// extendEnum makes enum a superset of the enum named baseName, which must be
+// among the already parsed enums: the base values come first, so that they
+// keep their int mapping.
+func extendEnum(enum *enumDef, enums []enumDef, baseName string) error {
+	base := findEnum(enums, baseName)
+	if base == nil {
+		return fmt.Errorf("extends unknown enum %s (it must be declared before)", baseName)
+	}
+
+	values := append([]valueInfo{}, base.Values...)
+	for _, v := range enum.Values {
+		v.Original = strings.TrimPrefix(v.Original, "+")
+		v.GoName = sanitizeGoName(v.Original)
+		for _, bv := range base.Values {
+			if bv.Original == v.Original {
+				return fmt.Errorf("value %q is already declared by %s", v.Original, baseName)
+			}
+		}
+		values = append(values, v)
+	}
+
+	baseCopy := *base
+	enum.Values = values
+	enum.Extends = &baseCopy
+	return nil
+}
+
+// restrictEnum makes enum a subset of the enum named parentName, which must
+// be among the already parsed enums: all the values of enum must be declared
+// by the parent.
+func restrictEnum(enum *enumDef, enums []enumDef, parentName string) error {
+	parent := findEnum(enums, parentName)
+	if parent == nil {
+		return fmt.Errorf("subset of unknown enum %s (it must be declared before)", parentName)
+	}
+
+	for i, v := range enum.Values {
+		found := false
+		for _, pv := range parent.Values {
+			if pv.Original == v.Original {
+				if v.Doc == "" {
+					enum.Values[i].Doc = pv.Doc
+				}
+				if v.Attrs == nil {
+					enum.Values[i].Attrs = pv.Attrs
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("value %q is not declared by %s", v.Original, parentName)
+		}
+	}
+
+	parentCopy := *parent
+	enum.SubsetOf = &parentCopy
+	return nil
+}
+
+// findEnum returns the enum with the given name, or nil.
+func findEnum(enums []enumDef, name string) *enumDef {
+	for i := range enums {
+		if enums[i].Name == name {
+			return &enums[i]
+		}
+	}
+	return nil
+}
+
+// parseValuesLine appends the values listed on one line of a directive,
+// reporting whether the line holds the closing parenthesis and, if so, the
+// options following it. Values can be double quoted (with backslash escapes)
+// or single quoted, and a backslash escapes the next character of unquoted
+// values, so that they can hold commas, parentheses and #. Balanced
+// parentheses and brackets are kept in unquoted values, with the commas they
+// enclose, unless the brackets end the value, then holding its attributes.
+func parseValuesLine(values []valueInfo, line string) ([]valueInfo, bool, string, error) {
+	var doc, options string
+	closed, found := false, false
+	var item valueToken
+	add := func() error {
+		v, ok := item.value()
+		item = valueToken{}
+		if ok && v.Original == "" {
+			return errors.New("empty quoted value")
+		}
+		if ok && strings.TrimSpace(v.Original) != v.Original {
+			return fmt.Errorf("value %q can't start or end with spaces, which Parse ignores", v.Original)
+		}
+		if ok {
+			values = append(values, v)
+			found = true
+		}
+		return nil
+	}
+
+	for i := 0; i < len(line) && !closed; i++ {
+		c := line[i]
+		switch {
+		case c == '"' || c == '\'':
+			end := i + 1
+			for ; end < len(line) && line[end] != c; end++ {
+				if c == '"' && line[end] == '\\' && end+1 < len(line) {
+					end++
+				}
+				item.add(line[end], true)
+			}
+			if end == len(line) {
+				return nil, false, "", fmt.Errorf("unterminated quoted value %s", line[i:])
+			}
+			item.quoted = true
+			i = end
+		case c == '\\' && i+1 < len(line):
+			i++
+			item.add(line[i], true)
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			doc = strings.TrimSpace(strings.TrimPrefix(line[i:], "#"))
+			i = len(line)
+		case c == '(' || c == '[':
+			end := matchingBracket(line, i)
+			if end < 0 {
+				return nil, false, "", fmt.Errorf("unbalanced %c in %s (quote the value)", c, line[i:])
+			}
+			// brackets ending a value hold its attributes
+			rest := strings.TrimLeft(line[end+1:], " \t")
+			if c == '[' && (rest == "" || rest[0] == ',' || rest[0] == ')' || (rest[0] == '#' && len(rest) < len(line[end+1:]))) {
+				item.attrs = parseValueAttrs(line[i+1 : end])
+			} else {
+				for _, b := range []byte(line[i : end+1]) {
+					item.add(b, false)
+				}
+			}
+			i = end
+		case c == ',':
+			if err := add(); err != nil {
+				return nil, false, "", err
+			}
+		case c == ')':
+			if err := add(); err != nil {
+				return nil, false, "", err
+			}
+			closed, options = true, line[i+1:]
+			if m := trailingCommentRegex.FindStringSubmatchIndex(options); m != nil {
+				doc = strings.TrimSpace(options[m[4]:m[5]])
+				options = options[:m[0]]
+			}
+		default:
+			item.add(c, false)
+		}
+	}
+	if !closed {
+		if err := add(); err != nil {
+			return nil, false, "", err
+		}
+	}
+
+	// a comment on a line without values continues the doc of the previous value
+	if doc != "" && len(values) > 0 {
+		last := &values[len(values)-1]
+		if found || last.Doc == "" {
+			last.Doc = doc
+		} else {
+			last.Doc += " " + doc
+		}
+	}
+	return values, closed, options, nil
+}
+
+// matchingBracket returns the index of the parenthesis or bracket closing the
+// one at index open of s, or -1 if it isn't closed.
+func matchingBracket(s string, open int) int {
+	closing := byte(')')
+	if s[open] == '[' {
+		closing = ']'
+	}
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case s[open]:
+			depth++
+		case closing:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// valueToken accumulates the bytes of a value of a directive.
+type valueToken struct {
+	buf []byte
+	// literal marks the bytes that were quoted or escaped, which are kept
+	// when trimming the surrounding spaces.
+	literal []bool
+	quoted  bool
+	attrs   map[string]string
+}
+
+func (t *valueToken) add(c byte, literal bool) {
+	t.buf = append(t.buf, c)
+	t.literal = append(t.literal, literal)
+}
+
+// text returns the value without the unquoted surrounding spaces.
+func (t *valueToken) text() string {
+	start, end := 0, len(t.buf)
+	for start < end && !t.literal[start] && (t.buf[start] == ' ' || t.buf[start] == '\t') {
+		start++
+	}
+	for end > start && !t.literal[end-1] && (t.buf[end-1] == ' ' || t.buf[end-1] == '\t') {
+		end--
+	}
+	return string(t.buf[start:end])
+}
+
+// value returns the value accumulated, or false if there is none (between
+// two commas).
+func (t *valueToken) value() (valueInfo, bool) {
+	text := t.text()
+	if text == "" && !t.quoted {
+		return valueInfo{}, false
+	}
+	value := valueInfo{Original: text, GoName: sanitizeGoName(text)}
+	if len(t.attrs) > 0 {
+		value.Attrs = t.attrs
+	}
+	return value, true
+}
+
+// parseValueAttrs parses the space separated key=value attributes of a value.
+// The validity of the keys and values is checked by checkValueAttrs.
+func parseValueAttrs(text string) map[string]string {
+	attrs := map[string]string{}
+	for _, field := range strings.Fields(text) {
+		key, value, _ := strings.Cut(field, "=")
+		attrs[key] = value
+	}
+	return attrs
+}
+
+// parseOptions applies the space separated options of a directive to opts.
+// Boolean features are enabled by their name (or name=true) and disabled by
+// no-name (or name=false).
+func parseOptions(opts *genOptions, text string) error {
+	boolOptions := map[string]*bool{
+		"yaml":          &opts.YAML,
+		"env":           &opts.Env,
+		"slugs":         &opts.Slugs,
+		"csv":           &opts.CSV,
+		"schema":        &opts.Schema,
+		"strict":        &opts.Strict,
+		"otel":          &opts.OTel,
+		"prometheus":    &opts.Prom,
+		"kubebuilder":   &opts.Kube,
+		"terraform":     &opts.TF,
+		"null-zero":     &opts.Null,
+		"firestore":     &opts.Fire,
+		"spanner":       &opts.Span,
+		"clickhouse":    &opts.CH,
+		"binary":        &opts.Binary,
+		"protojson":     &opts.PJSON,
+		"hash":          &opts.Hash,
+		"list":          &opts.List,
+		"set":           &opts.Set,
+		"set-bitmask":   &opts.SetBM,
+		"counts":        &opts.Counts,
+		"fuzzy":         &opts.Fuzzy,
+		"fixed-width":   &opts.Fixed,
+		"packed":        &opts.Packed,
+		"slices":        &opts.Slices,
+		"swag":          &opts.Swag,
+		"huma":          &opts.Huma,
+		"select":        &opts.Select,
+		"sorted":        &opts.Sorted,
+		"elasticsearch": &opts.ES,
+		"mongo":         &opts.Mongo,
+		"kafka":         &opts.Kafka,
+		"feature-flag":  &opts.FeatureFlag,
+		"stringer":      &opts.Stringer,
+		"test-only":     &opts.TestOnly,
+	}
+	stringOptions := map[string]*string{
+		"csv-separator":  &opts.CSVSeparator,
+		"avro-namespace": &opts.AvroNS,
+	}
+
+	for _, field := range strings.Fields(text) {
+		key, value, hasValue := strings.Cut(field, "=")
+		switch key {
+		case "style":
+			switch value {
+			case "struct", "const", "int":
+				opts.Style = value
+			default:
+				return fmt.Errorf("invalid style %q (must be struct, const or int)", value)
+			}
+			continue
+		case "on-parse-error":
+			switch value {
+			case "keep", "zero", "first":
+				opts.OnParseError = value
+			default:
+				return fmt.Errorf("invalid on-parse-error %q (must be keep, zero or first)", value)
+			}
+			continue
+		case "zero-string":
+			switch value {
+			case "empty", "invalid", "default":
+				opts.ZeroString = value
+			default:
+				return fmt.Errorf("invalid zero-string %q (must be empty, invalid or default)", value)
+			}
+			continue
+		case "sql-builder":
+			switch value {
+			case "squirrel", "goqu":
+				opts.SQLBuilder = value
+			default:
+				return fmt.Errorf("invalid sql-builder %q (must be squirrel or goqu)", value)
+			}
+			continue
+		case "var-names":
+			switch value {
+			case "lower", "namespaced":
+				opts.VarNames = value
+			default:
+				return fmt.Errorf("invalid var-names %q (must be lower or namespaced)", value)
+			}
+			continue
+		case "require-version":
+			if err := checkRequiredVersion(value); err != nil {
+				return err
+			}
+			continue
+		case "skip":
+			if _, err := parseSymbolList(value); err != nil {
+				return err
+			}
+			opts.Skip = strings.TrimPrefix(opts.Skip+","+value, ",")
+			continue
+		case "rename":
+			if _, err := parseRenames(value); err != nil {
+				return err
+			}
+			opts.Rename = strings.TrimPrefix(opts.Rename+","+value, ",")
+			continue
+		case "out":
+			if !strings.HasSuffix(value, ".go") || strings.HasSuffix(value, "_test.go") || strings.ContainsAny(value, `/\`) {
+				return fmt.Errorf("invalid out %q (must be the name of a non-test .go file, generated next to the output)", value)
+			}
+			opts.Out = value
+			continue
+		}
+		if target, ok := stringOptions[key]; ok {
+			if value == "" {
+				return fmt.Errorf("missing value for option %s", key)
+			}
+			*target = value
+			continue
+		}
+
+		enabled := true
+		if name, ok := strings.CutPrefix(key, "no-"); ok && !hasValue {
+			key, enabled = name, false
+		}
+		target, ok := boolOptions[key]
+		if !ok {
+			return fmt.Errorf("unknown option %q", key)
+		}
+		if hasValue {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q for option %s", value, key)
+			}
+			enabled = b
+		}
+		*target = enabled
+	}
+	return nil
+}
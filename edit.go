@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// editCmd interactively edits the enum directives of a file.
+type editCmd struct {
+	File   string `help:"File declaring the enums" short:"f" required:""`
+	Output string `help:"Output file regenerated after writing the directives (with the flags of the go:generate line of the file, if any)" short:"o"`
+}
+
+// enumDirective is an ENUM directive as written in a file.
+type enumDirective struct {
+	// Start and End are the first and last lines of the directive (1-based).
+	Start, End int
+	// Indent is the text preceding the "//" of the first line.
+	Indent string
+	Name   string
+	// Relation and Base are set for the enums extending or restricting another.
+	Relation, Base string
+	Values         []valueInfo
+	Options        string
+}
+
+const editHelp = `Commands:
+  list                          list the enums
+  show <enum>                   show the values of an enum with their ints
+  add <enum> <value> [doc...]   append a value
+  rename <enum> <value> <new>   change the string of a value
+  move <enum> <value> <index>   move a value (changing the ints of the following ones)
+  remove <enum> <value>         remove a value
+  write                         rewrite the directives (and regenerate with -o)
+  quit                          leave without writing
+Values with spaces can be double quoted.
+`
+
+// Run reads commands from stdin until quit or the end of the input.
+func (c *editCmd) Run() error {
+	data, err := os.ReadFile(c.File)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(normalizeNewlines(data)), "\n")
+	directives, err := findDirectives(c.File, lines)
+	if err != nil {
+		return err
+	}
+	if len(directives) == 0 {
+		return fmt.Errorf("no enum definitions found in %s", c.File)
+	}
+
+	out := os.Stdout
+	fmt.Fprint(out, editHelp)
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		args, err := splitArgs(scanner.Text())
+		if err != nil {
+			fmt.Fprintln(out, "error:", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "quit", "exit":
+			return nil
+		case "write":
+			if err := c.write(lines, directives); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "written", c.File)
+			return nil
+		case "help":
+			fmt.Fprint(out, editHelp)
+		default:
+			if err := editDirectives(out, directives, args); err != nil {
+				fmt.Fprintln(out, "error:", err)
+			}
+		}
+	}
+}
+
+// write rewrites the directives in the file and regenerates the output, with
+// the flags of the go:generate line of the file running the generator, if
+// any.
+func (c *editCmd) write(lines []string, directives []enumDirective) error {
+	if err := writeDirectives(c.File, lines, directives); err != nil {
+		return err
+	}
+	if c.Output == "" {
+		return nil
+	}
+
+	args, ok, err := generateLine(c.File, lines)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		opts := defaultGenOptions()
+		minGo, err := minGoVersion("", c.File)
+		if err != nil {
+			return err
+		}
+		opts.MinGo = minGo
+		return processFile(c.File, c.Output, opts)
+	}
+	gen, err := generateCommand(filepath.Dir(c.File), args)
+	if err != nil {
+		return err
+	}
+	if err := gen.validate(); err != nil {
+		return err
+	}
+	_, err = gen.generate(c.File, c.Output)
+	return err
+}
+
+// writeDirectives rewrites the directives (in order of position) among the
+// lines of file, keeping its line endings, and leaving it unchanged if the
+// result doesn't parse.
+func writeDirectives(file string, lines []string, directives []enumDirective) error {
+	// replace from the last directive, so that the line numbers of the previous ones stay valid
+	for i := len(directives) - 1; i >= 0; i-- {
+		d := directives[i]
+		lines = append(lines[:d.Start-1], append(d.format(), lines[d.End:]...)...)
+	}
+	original, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := writeOutput(file, []byte(strings.Join(lines, fileNewline(original)))); err != nil {
+		return err
+	}
+	// the checks above don't see the values of the base enums, so parse the result
+	if _, err := loadFile(file, defaultGenOptions()); err != nil {
+		if restoreErr := writeOutput(file, original); restoreErr != nil {
+			return restoreErr
+		}
+		return fmt.Errorf("%s left unchanged: %w", file, err)
+	}
+	return nil
+}
+
+// editDirectives runs a command other than write and quit.
+func editDirectives(out io.Writer, directives []enumDirective, args []string) error {
+	if args[0] == "list" {
+		for _, d := range directives {
+			fmt.Fprintf(out, "%s (%d values)\n", d.Name, len(d.Values))
+		}
+		return nil
+	}
+
+	arity := map[string]int{"show": 2, "add": 3, "rename": 4, "move": 4, "remove": 3}
+	n, ok := arity[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q (type help for the list)", args[0])
+	}
+	if len(args) < n || (len(args) > n && args[0] != "add") {
+		return fmt.Errorf("wrong number of arguments for %s", args[0])
+	}
+	var d *enumDirective
+	for i := range directives {
+		if directives[i].Name == args[1] {
+			d = &directives[i]
+		}
+	}
+	if d == nil {
+		return fmt.Errorf("unknown enum %s", args[1])
+	}
+
+	if args[0] == "show" {
+		for i, v := range d.Values {
+			fmt.Fprintf(out, "%3d  %s", i, v.Original)
+			if v.Doc != "" {
+				fmt.Fprintf(out, "  # %s", v.Doc)
+			}
+			fmt.Fprintln(out)
+		}
+		return nil
+	}
+	if args[0] == "add" {
+		if err := checkNewValue(d.Values, args[2], -1); err != nil {
+			return err
+		}
+		d.Values = append(d.Values, valueInfo{
+			Original: args[2],
+			GoName:   sanitizeGoName(args[2]),
+			Doc:      strings.Join(args[3:], " "),
+		})
+		return nil
+	}
+
+	i := directiveValueIndex(d.Values, args[2])
+	if i < 0 {
+		return fmt.Errorf("%s has no value %q", d.Name, args[2])
+	}
+	switch args[0] {
+	case "rename":
+		if err := checkNewValue(d.Values, args[3], i); err != nil {
+			return err
+		}
+		d.Values[i].Original, d.Values[i].GoName = args[3], sanitizeGoName(args[3])
+	case "move":
+		j, err := strconv.Atoi(args[3])
+		if err != nil || j < 0 || j >= len(d.Values) {
+			return fmt.Errorf("invalid index %q (must be between 0 and %d)", args[3], len(d.Values)-1)
+		}
+		v := d.Values[i]
+		d.Values = append(d.Values[:i], d.Values[i+1:]...)
+		d.Values = append(d.Values[:j], append([]valueInfo{v}, d.Values[j:]...)...)
+	case "remove":
+		if len(d.Values) == 1 {
+			return fmt.Errorf("can't remove the last value of %s", d.Name)
+		}
+		d.Values = append(d.Values[:i], d.Values[i+1:]...)
+	}
+	return nil
+}
+
+// directiveValueIndex returns the index of value among values, ignoring the
+// optional "+" marking the values added by an extending enum, or -1.
+func directiveValueIndex(values []valueInfo, value string) int {
+	for i, v := range values {
+		if strings.TrimPrefix(v.Original, "+") == strings.TrimPrefix(value, "+") {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkNewValue reports whether value can be given to the value at index
+// self (-1 for a new value) without colliding with the others, either in
+// Parse (which ignores case) or in the member identifiers.
+func checkNewValue(values []valueInfo, value string, self int) error {
+	if value == "" || strings.TrimSpace(value) != value {
+		return fmt.Errorf("invalid value %q (can't be empty or start or end with spaces)", value)
+	}
+	for i, v := range values {
+		if i == self {
+			continue
+		}
+		if strings.EqualFold(v.Original, value) {
+			return fmt.Errorf("value %q collides with %q", value, v.Original)
+		}
+		if strings.Title(v.GoName) == strings.Title(sanitizeGoName(value)) {
+			return fmt.Errorf("value %q has the same Go name as %q", value, v.Original)
+		}
+	}
+	return nil
+}
+
+// findDirectives returns the ENUM directives among the lines of file,
+// failing at the first unterminated or invalid one.
+func findDirectives(file string, lines []string) ([]enumDirective, error) {
+	var directives []enumDirective
+	text := strings.Join(lines, "\n")
+	scanner := newLineScanner(strings.NewReader(text), len(text)+1)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		m := enumRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		d := enumDirective{
+			Start:    lineNo,
+			Indent:   scanner.Text()[:strings.Index(scanner.Text(), "//")],
+			Name:     m[1],
+			Relation: m[2],
+			Base:     m[3],
+		}
+		values, options, err := readValues(scanner, m[4], &lineNo)
+		if errors.Is(err, errUnterminated) {
+			return nil, errorAt(file, d.Start, "unterminated-directive", "unterminated ENUM directive %s", d.Name)
+		}
+		if err != nil {
+			return nil, errorAt(file, d.Start, "invalid-enum", "ENUM %s: %v", d.Name, err)
+		}
+		d.End, d.Values, d.Options = lineNo, values, strings.TrimSpace(options)
+		directives = append(directives, d)
+	}
+	return directives, nil
+}
+
+// format returns the lines of the directive: a single one, or one per value
+// when some of them are documented.
+func (d enumDirective) format() []string {
+	head := d.Indent + "// ENUM " + d.Name
+	if d.Relation != "" {
+		head += " " + d.Relation + " " + d.Base
+	}
+	tail := ")"
+	if d.Options != "" {
+		tail += " " + d.Options
+	}
+
+	documented := false
+	values := make([]string, len(d.Values))
+	for i, v := range d.Values {
+		values[i] = v.directiveText()
+		documented = documented || v.Doc != ""
+	}
+	if !documented {
+		return []string{head + " (" + strings.Join(values, ", ") + tail}
+	}
+
+	lines := []string{head + " ("}
+	for i, v := range d.Values {
+		line := d.Indent + "//   " + v.directiveText()
+		if i < len(d.Values)-1 {
+			line += ","
+		}
+		if v.Doc != "" {
+			line += " # " + v.Doc
+		}
+		lines = append(lines, line)
+	}
+	return append(lines, d.Indent+"// "+tail)
+}
+
+// directiveText returns the value as written in a directive, with its
+// attributes, double quoted if it holds characters with a meaning in
+// directives.
+func (v valueInfo) directiveText() string {
+	text := v.Original
+	if strings.ContainsAny(text, `,()#[]"'\`) {
+		text = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+	}
+	if len(v.Attrs) == 0 {
+		return text
+	}
+	attrs := make([]string, 0, len(v.Attrs))
+	for key, value := range v.Attrs {
+		attrs = append(attrs, key+"="+value)
+	}
+	sort.Strings(attrs)
+	return text + " [" + strings.Join(attrs, " ") + "]"
+}
+
+// splitArgs splits a command line on spaces, keeping double quoted strings whole.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return args, nil
+		}
+		if line[0] != '"' {
+			arg, rest, _ := strings.Cut(line, " ")
+			args, line = append(args, arg), rest
+			continue
+		}
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return nil, errors.New("unterminated quoted string")
+		}
+		args, line = append(args, line[1:end+1]), line[end+2:]
+	}
+}
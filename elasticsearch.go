@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// maxESMetaLen is the longest value of the meta parameter of an Elasticsearch
+// field mapping.
+const maxESMetaLen = 500
+
+// esMapping returns the Elasticsearch (and OpenSearch) mapping of the fields
+// storing enum values: a keyword, matched exactly, with the values listed in
+// its metadata when they fit.
+func esMapping(enum enumDef) (string, error) {
+	meta := map[string]string{"enum": enum.Name}
+	values := make([]string, len(enum.Values))
+	for i, v := range enum.Values {
+		values[i] = v.Original
+	}
+	if list := strings.Join(values, ","); len(list) <= maxESMetaLen {
+		meta["values"] = list
+	}
+	data, err := json.Marshal(map[string]interface{}{"type": "keyword", "meta": meta})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import "errors"
+
+// Exit codes of the generate command, telling the classes of failures apart.
+const (
+	exitError    = 1 // any other failure
+	exitInvalid  = 3 // invalid directive or option
+	exitNoEnums  = 4 // no enum directive in an input file
+	exitWrite    = 5 // output file that couldn't be written
+	exitOutdated = 6 // out of date output in --check mode
+)
+
+// writeError is an error writing an output file.
+type writeError struct {
+	err error
+}
+
+func (e *writeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *writeError) Unwrap() error {
+	return e.err
+}
+
+// exitCode returns the exit code reporting err.
+func exitCode(err error) int {
+	var d *diagnostic
+	var w *writeError
+	switch {
+	case errors.Is(err, errOutdated):
+		return exitOutdated
+	case errors.As(err, &w):
+		return exitWrite
+	case errors.As(err, &d) && d.Code == "no-enums":
+		return exitNoEnums
+	case errors.As(err, &d):
+		return exitInvalid
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// enumField is a field of a struct typed as one of the generated enums.
+type enumField struct {
+	Struct, Field string
+	// Name is the name of the field in JSON.
+	Name string
+	Enum enumDef
+}
+
+// fieldConstsOutput returns the file the field constants of output are
+// generated into.
+func fieldConstsOutput(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_fields.go"
+}
+
+// renderFieldConsts returns the code declaring, for each field typed as one of
+// the enums of def (or a pointer or slice of them) of the structs of the
+// package of filename, the constant of its JSON name and the functions
+// pairing it with values, for query builders. It returns nil if there are no
+// such fields.
+func renderFieldConsts(filename string, outputs []string, def fileDef) ([]byte, error) {
+	fields, err := enumFields(filename, outputs, def)
+	if err != nil || len(fields) == 0 {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(generatedHeader("go-safe-enum-generator") + "\n\n")
+	fmt.Fprintf(&buf, "package %s\n", def.Package)
+	for _, f := range fields {
+		prefix := f.Struct + f.Field
+		fmt.Fprintf(&buf, "\n// %sField is the JSON name of the %s.%s field.\n", prefix, f.Struct, f.Field)
+		fmt.Fprintf(&buf, "const %sField = %s\n", prefix, strconv.Quote(f.Name))
+		fmt.Fprintf(&buf, "\n// %sEq returns the %s.%s field and the string of v, for filters of query\n// builders (e.g. bson.M{field: value}).\n", prefix, f.Struct, f.Field)
+		fmt.Fprintf(&buf, "func %sEq(v %s) (field, value string) {\n\treturn %sField, v.String()\n}\n", prefix, f.Enum.Name, prefix)
+		fmt.Fprintf(&buf, "\n// %sIn returns the %s.%s field and the strings of vs, for filters of\n// query builders matching any of them.\n", prefix, f.Struct, f.Field)
+		fmt.Fprintf(&buf, "func %sIn(vs ...%s) (field string, values []string) {\n", prefix, f.Enum.Name)
+		fmt.Fprintf(&buf, "\tvalues = make([]string, len(vs))\n\tfor i, v := range vs {\n\t\tvalues[i] = v.String()\n\t}\n\treturn %sField, values\n}\n", prefix)
+	}
+	return buf.Bytes(), nil
+}
+
+// enumFields returns the fields typed as the enums of def of the named struct
+// types declared by the hand-written Go files of the package of filename, but
+// the outputs, sorted by struct and field.
+func enumFields(filename string, outputs []string, def fileDef) ([]enumField, error) {
+	enums := map[string]enumDef{}
+	for _, enum := range def.Enums {
+		if !enum.TestOnly {
+			enums[enum.Name] = enum
+		}
+	}
+	isOutput := map[string]bool{}
+	for _, output := range outputs {
+		isOutput[filepath.Clean(output)] = true
+	}
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var fields []enumField
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || isOutput[filepath.Clean(file)] {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ParseComments)
+		if err != nil || f.Name.Name != def.Package || ast.IsGenerated(f) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					enum, ok := enums[fieldEnumName(field.Type)]
+					if !ok {
+						continue
+					}
+					for _, name := range field.Names {
+						if jsonName, ok := fieldJSONName(name.Name, field.Tag); ok {
+							fields = append(fields, enumField{Struct: ts.Name.Name, Field: name.Name, Name: jsonName, Enum: enum})
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Struct != fields[j].Struct {
+			return fields[i].Struct < fields[j].Struct
+		}
+		return fields[i].Field < fields[j].Field
+	})
+	return fields, nil
+}
+
+// fieldJSONName returns the name of a field in JSON, from its json tag if
+// set, and false if it isn't marshaled.
+func fieldJSONName(field string, tag *ast.BasicLit) (string, bool) {
+	if !token.IsExported(field) {
+		return "", false
+	}
+	if tag == nil {
+		return field, true
+	}
+	value, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return field, true
+	}
+	name, _, _ := strings.Cut(reflect.StructTag(value).Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field, true
+	}
+	return name, true
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fieldDocPrefix starts the line of the field doc comments listing the
+// allowed values, which is replaced when they change.
+const fieldDocPrefix = "Allowed values: "
+
+// updateFieldDocs adds or refreshes the allowed values line of the doc
+// comment of the struct fields typed as one of the enums of def, in the Go
+// files of the package of filename (but output and the other generated
+// files). With check, it writes nothing and fails if a file isn't up to date.
+func updateFieldDocs(filename, output string, def fileDef, check bool) error {
+	enums := map[string]enumDef{}
+	for _, enum := range def.Enums {
+		enums[enum.Name] = enum
+	}
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.go"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || (output != "" && filepath.Clean(file) == filepath.Clean(output)) {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		updated, err := fieldDocs(file, data, def.Package, enums)
+		if err != nil {
+			logger.Debug("skipping file for field docs", "file", file, "error", err)
+			continue
+		}
+		if updated == nil {
+			continue
+		}
+		if check {
+			return fmt.Errorf("the field docs of %s are %w (run the generator again)", file, errOutdated)
+		}
+		logger.Info("updating field docs", "file", file)
+		if err := writeOutput(file, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldDocs returns data with the field doc comments updated, or nil if they
+// already are, or if the file isn't a hand-written one of package pkg.
+func fieldDocs(filename string, data []byte, pkg string, enums map[string]enumDef) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, data, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if f.Name.Name != pkg || ast.IsGenerated(f) {
+		return nil, nil
+	}
+
+	// the lines match the positions of the parser, which only breaks lines
+	// at LF: the CR of CRLF endings is trimmed, then restored
+	lines := strings.Split(string(data), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+	// the new lines by line index, replacing the existing line or inserted
+	// before it
+	replaced := map[int]string{}
+	inserted := map[int][]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			enum, ok := enums[fieldEnumName(field.Type)]
+			if !ok {
+				continue
+			}
+			line := fset.Position(field.Pos()).Line - 1
+			indent := lines[line][:len(lines[line])-len(strings.TrimLeft(lines[line], " \t"))]
+			doc := indent + "// " + fieldDocPrefix + enum.ValueList() + "."
+			if field.Doc == nil {
+				inserted[line] = []string{doc}
+				continue
+			}
+			found := false
+			for _, c := range field.Doc.List {
+				if strings.HasPrefix(strings.TrimPrefix(c.Text, "// "), fieldDocPrefix) {
+					docLine := fset.Position(c.Pos()).Line - 1
+					if lines[docLine] != doc {
+						replaced[docLine] = doc
+					}
+					found = true
+				}
+			}
+			if !found {
+				inserted[fset.Position(field.Doc.End()).Line] = []string{indent + "//", doc}
+			}
+		}
+		return true
+	})
+	if len(replaced) == 0 && len(inserted) == 0 {
+		return nil, nil
+	}
+
+	var out []string
+	for i, line := range lines {
+		out = append(out, inserted[i]...)
+		if r, ok := replaced[i]; ok {
+			line = r
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, fileNewline(data))), nil
+}
+
+// fieldEnumName returns the name of the type of a field typed as T, *T or
+// []T, or "" for the other types.
+func fieldEnumName(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return fieldEnumName(t.X)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return fieldEnumName(t.Elt)
+		}
+	}
+	return ""
+}
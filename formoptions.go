@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// formOption is an option of a form control choosing an enum value, as
+// expected by react-hook-form and most component libraries.
+type formOption struct {
+	Value      string `json:"value"`
+	Label      string `json:"label"`
+	Deprecated bool   `json:"deprecated"`
+	// Labels are the translated labels by language, if any.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// formSchema is the JSON schema of an enum rendered as a select control with
+// titled options by JSON Forms.
+type formSchema struct {
+	Type  string           `json:"type"`
+	OneOf []formSchemaItem `json:"oneOf"`
+}
+
+type formSchemaItem struct {
+	Const      string `json:"const"`
+	Title      string `json:"title"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+// formEnum holds the form options and the JSON Forms schema of an enum.
+type formEnum struct {
+	Options []formOption `json:"options"`
+	Schema  formSchema   `json:"schema"`
+}
+
+// writeFormOptions writes the form options of the enums of def to output, as
+// a TypeScript module if it ends with .ts, as a JSON object by enum name
+// otherwise.
+func writeFormOptions(output string, def fileDef) error {
+	enums := make(map[string]formEnum, len(def.Enums))
+	for _, enum := range def.Enums {
+		enums[enum.Name] = newFormEnum(enum)
+	}
+	if filepath.Ext(output) != ".ts" {
+		data, err := json.MarshalIndent(enums, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding form options: %w", err)
+		}
+		return writeOutput(output, append(data, '\n'))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(generatedHeader("go-safe-enum-generator") + "\n\n")
+	buf.WriteString("export interface EnumOption {\n  value: string;\n  label: string;\n  deprecated: boolean;\n  labels?: Record<string, string>;\n}\n")
+	for _, enum := range def.Enums {
+		options, err := json.MarshalIndent(enums[enum.Name].Options, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding form options of %s: %w", enum.Name, err)
+		}
+		schema, err := json.MarshalIndent(enums[enum.Name].Schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding form schema of %s: %w", enum.Name, err)
+		}
+		fmt.Fprintf(&buf, "\nexport const %sOptions: EnumOption[] = %s;\n", enum.Name, options)
+		fmt.Fprintf(&buf, "\nexport const %sSchema = %s as const;\n", enum.Name, schema)
+	}
+	return writeOutput(output, buf.Bytes())
+}
+
+// newFormEnum returns the form options and schema of enum, labeled with the
+// values (and their translations), deprecated when their doc says so.
+func newFormEnum(enum enumDef) formEnum {
+	form := formEnum{Schema: formSchema{Type: "string"}}
+	for _, v := range enum.Values {
+		option := formOption{Value: v.Original, Label: v.Original, Deprecated: deprecated(v.Doc)}
+		for lang, labels := range enum.Labels {
+			if label, ok := labels[v.Original]; ok {
+				if option.Labels == nil {
+					option.Labels = map[string]string{}
+				}
+				option.Labels[lang] = label
+			}
+		}
+		form.Options = append(form.Options, option)
+		form.Schema.OneOf = append(form.Schema.OneOf, formSchemaItem{Const: v.Original, Title: v.Original, Deprecated: option.Deprecated})
+	}
+	return form
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fuzzyKey returns the form of s compared by ParseFuzzy: lower case, without
+// separators. The generated {{name}}FuzzyKey functions must match it.
+func fuzzyKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -_./", r) {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+// checkFuzzyKeys checks that the values of enum can be told apart by ParseFuzzy.
+func checkFuzzyKeys(enum enumDef) error {
+	seen := map[string]string{}
+	for _, v := range enum.Values {
+		key := fuzzyKey(v.Original)
+		if key == "" {
+			return fmt.Errorf("value %q is only made of separators, which fuzzy parsing ignores", v.Original)
+		}
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("values %q and %q only differ by case or separators, which fuzzy parsing ignores", other, v.Original)
+		}
+		seen[key] = v.Original
+	}
+	return nil
+}
@@ -1,5 +1,14 @@
 module github.com/panta/go-safe-enum-generator
 
-go 1.22
+go 1.22.0
 
-require github.com/alecthomas/kong v1.6.0
+require (
+	github.com/alecthomas/kong v1.6.0
+	golang.org/x/mod v0.23.0
+	golang.org/x/tools v0.30.0
+	google.golang.org/protobuf v1.36.5
+)
+
+require (
+	golang.org/x/sync v0.11.0 // indirect
+)
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// generateLine returns the arguments of the go:generate line among the lines
+// of file running the generator (installed or with go run), with $GOFILE,
+// $GOPACKAGE, $DOLLAR and the environment variables expanded as by go
+// generate, and whether there is one.
+func generateLine(file string, lines []string) ([]string, bool, error) {
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "//go:generate ") {
+			continue
+		}
+		fields, err := splitArgs(strings.TrimPrefix(line, "//go:generate "))
+		if err != nil {
+			return nil, false, errorAt(file, i+1, "invalid-generate", "go:generate line: %v", err)
+		}
+		for j, field := range fields {
+			tool, _, _ := strings.Cut(path.Base(field), "@")
+			if tool != "go-safe-enum-generator" {
+				continue
+			}
+			pkgName, _ := getPackageName(file)
+			args := fields[j+1:]
+			for k, arg := range args {
+				args[k] = os.Expand(arg, func(name string) string {
+					switch name {
+					case "GOFILE":
+						return filepath.Base(file)
+					case "GOPACKAGE":
+						return pkgName
+					case "DOLLAR":
+						return "$"
+					}
+					return os.Getenv(name)
+				})
+			}
+			return args, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// generateCommand parses args, from the go:generate line of a file in dir, as
+// the command line of the generate command. The paths are resolved in dir,
+// where go generate runs the generator.
+func generateCommand(dir string, args []string) (*generateCmd, error) {
+	var cli struct {
+		Verbose   int    `short:"v" type:"counter"`
+		LogFormat string `enum:"text,json" default:"text"`
+
+		Generate generateCmd `cmd:"" default:"withargs"`
+	}
+	parser, err := kong.New(&cli)
+	if err != nil {
+		return nil, err
+	}
+	// kong resolves the path flags against the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, err
+	}
+	_, err = parser.Parse(args)
+	if err := os.Chdir(wd); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing the go:generate line: %w", err)
+	}
+
+	gen := &cli.Generate
+	for i, file := range gen.File {
+		gen.File[i] = inDir(dir, file)
+	}
+	if gen.Output != "" {
+		gen.Output = inDir(dir, gen.Output)
+	}
+	return gen, nil
+}
+
+// inDir returns file, resolved in dir if relative.
+func inDir(dir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goldenData is the content of the golden file of an enum. The generated test
+// builds the same structure from the compiled enum and compares the JSON encodings.
+type goldenData struct {
+	Slugs []string       `json:"slugs"`
+	Ints  map[int]string `json:"ints"`
+}
+
+// goldenPath returns the path of the golden file of the named enum, relative
+// to the package directory.
+func goldenPath(name string) string {
+	return "testdata/" + name + ".enum.golden.json"
+}
+
+// writeGolden writes a test asserting the slugs and int mappings of enums
+// next to output, and creates the golden files that are still missing.
+// Existing golden files are never overwritten: the generated test is meant to
+// fail when the values change, unless UPDATE_ENUM_GOLDEN is set.
+func writeGolden(output, pkgName string, enums []enumDef) error {
+	if output == "" {
+		return errors.New("golden tests require an output file")
+	}
+	dir := filepath.Dir(output)
+
+	for _, enum := range enums {
+		path := filepath.Join(dir, goldenPath(enum.Name))
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		data, err := goldenJSON(enum)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating testdata directory: %w", err)
+		}
+		if err := writeOutput(path, data); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	data := struct {
+		Package string
+		Enums   []enumDef
+	}{pkgName, enums}
+	if err := goldenTemplate.Execute(&buf, data); err != nil {
+		return fmt.Errorf("executing golden template: %w", err)
+	}
+	testFile := strings.TrimSuffix(output, ".go") + "_golden_test.go"
+	return writeOutput(testFile, normalizeNewlines(buf.Bytes()))
+}
+
+// goldenJSON returns the golden file content for enum, encoded exactly as
+// the generated test does.
+func goldenJSON(enum enumDef) ([]byte, error) {
+	golden := goldenData{Ints: map[int]string{}}
+	for i, v := range enum.Values {
+		golden.Slugs = append(golden.Slugs, v.Original)
+		golden.Ints[i] = v.Original
+	}
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding golden data for %s: %w", enum.Name, err)
+	}
+	return append(data, '\n'), nil
+}
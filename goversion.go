@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var goDirectiveRegex = regexp.MustCompile(`(?m)^go\s+(\S+)\s*$`)
+
+// minGoVersion returns the minimum Go version the generated code can rely
+// on: the given one if set, otherwise the go directive of the go.mod file of
+// the module containing filename (or "" if none is found or filename is
+// empty).
+func minGoVersion(given, filename string) (string, error) {
+	if given != "" {
+		if !version.IsValid("go" + given) {
+			return "", fmt.Errorf("invalid Go version %q", given)
+		}
+		return given, nil
+	}
+
+	if filename == "" {
+		return "", nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return "", nil
+	}
+	for {
+		data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			if m := goDirectiveRegex.FindSubmatch(data); m != nil && version.IsValid("go"+string(m[1])) {
+				return string(m[1]), nil
+			}
+			return "", nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// GoAtLeast reports whether the generated code can use features of Go v.
+func (e enumDef) GoAtLeast(v string) bool {
+	return e.MinGo != "" && version.Compare("go"+e.MinGo, "go"+v) >= 0
+}
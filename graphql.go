@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	graphqlNameRegex = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+	moduleRegex      = regexp.MustCompile(`(?m)^module\s+"?([^"\s]+)"?\s*$`)
+)
+
+// graphqlName returns the GraphQL name of v: its Go identifier in
+// SCREAMING_SNAKE_CASE, as GraphQL enum values can't hold arbitrary strings.
+func graphqlName(v valueInfo) (string, error) {
+	name := screamingSnake(strings.Title(v.GoName))
+	if !graphqlNameRegex.MatchString(name) || name == "TRUE" || name == "FALSE" || name == "NULL" {
+		return "", fmt.Errorf("value %q can't be a GraphQL enum value (%s)", v.Original, name)
+	}
+	return name, nil
+}
+
+// graphqlString returns s as a GraphQL string literal, whose escapes are the
+// JSON ones.
+func graphqlString(s string) (string, error) {
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+// deprecated reports whether doc marks a value as deprecated, starting with
+// "Deprecated:" as in Go.
+func deprecated(doc string) bool {
+	return strings.HasPrefix(doc, "Deprecated:")
+}
+
+// deprecationReason returns the text following the "Deprecated:" prefix of doc.
+func deprecationReason(doc string) string {
+	return strings.TrimSpace(strings.TrimPrefix(doc, "Deprecated:"))
+}
+
+// gqlgenModelsPath returns the path of the gqlgen models snippet written next
+// to the GraphQL schema graphqlOut: schema.graphql gives schema.gqlgen.yml.
+func gqlgenModelsPath(graphqlOut string) string {
+	return strings.TrimSuffix(graphqlOut, filepath.Ext(graphqlOut)) + ".gqlgen.yml"
+}
+
+// writeGQLGenModels writes to output the models section of gqlgen.yml binding
+// the GraphQL enums of def to the Go types generated in dir. It's skipped with
+// a warning when dir isn't in a module, as the types can't be referenced.
+func writeGQLGenModels(output, dir string, def fileDef) error {
+	pkgPath, err := packageImportPath(dir)
+	if err != nil {
+		return err
+	}
+	if pkgPath == "" {
+		logger.Warn("no go.mod found, skipping the gqlgen models", "dir", dir)
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString(generatedComment("#", "go-safe-enum-generator") + "\n")
+	buf.WriteString("# Merge into the models section of gqlgen.yml.\n")
+	buf.WriteString("models:\n")
+	for _, enum := range def.Enums {
+		fmt.Fprintf(&buf, "  %s:\n    model: %s.%s\n", enum.Name, pkgPath, enum.Name)
+	}
+	return writeOutput(output, buf.Bytes())
+}
+
+// packageImportPath returns the import path of the package in dir, from the
+// module path of the go.mod file of the module containing it, or "" if none
+// is found.
+func packageImportPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for root := dir; ; {
+		data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			m := moduleRegex.FindSubmatch(data)
+			if m == nil {
+				return "", fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+			}
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				return string(m[1]), nil
+			}
+			return string(m[1]) + "/" + filepath.ToSlash(rel), nil
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return "", nil
+		}
+		root = parent
+	}
+}
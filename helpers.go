@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+)
+
+// helpersFilename is the name of the file holding the generic helpers shared
+// by all the enums of a package when --shared-helpers is enabled.
+const helpersFilename = "enum_helpers_gen.go"
+
+// writeHelpers writes the shared helpers file for package pkgName in dir.
+func writeHelpers(dir, pkgName string) error {
+	var buf bytes.Buffer
+	if err := helpersTemplate.Execute(&buf, struct{ Package string }{pkgName}); err != nil {
+		return fmt.Errorf("executing helpers template: %w", err)
+	}
+	return writeOutput(filepath.Join(dir, helpersFilename), normalizeNewlines(buf.Bytes()))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"text/template"
+)
+
+// writeIDL writes the enums of def to output as an IDL file rendered by tmpl.
+// Members are named after their Go identifiers and numbered after their int
+// mapping, which is what both Thrift and FlatBuffers put on the wire.
+func writeIDL(output string, tmpl *template.Template, def fileDef) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, def); err != nil {
+		return fmt.Errorf("executing %s template: %w", tmpl.Name(), err)
+	}
+	return writeOutput(output, normalizeNewlines(buf.Bytes()))
+}
+
+// fbsType returns the smallest FlatBuffers integer type able to hold the int
+// mappings of values.
+func fbsType(values []valueInfo) string {
+	switch {
+	case len(values) <= math.MaxUint8+1:
+		return "ubyte"
+	case len(values) <= math.MaxUint16+1:
+		return "ushort"
+	default:
+		return "uint"
+	}
+}
@@ -0,0 +1,439 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// importCmd converts the enums declared for go-enum and enumer to directives.
+type importCmd struct {
+	File  []string `help:"File declaring enums for go-enum or enumer (repeatable)" short:"f" required:"" sep:"none"`
+	Write bool     `help:"Replace the declarations and go:generate lines of the files with directives, instead of printing the directives"`
+}
+
+// foreignEnum is an enum declared for another generator.
+type foreignEnum struct {
+	Directive enumDirective
+	// Decls are the declarations of the enum: the first one is replaced by the
+	// directive, the others are removed.
+	Decls []*ast.GenDecl
+}
+
+// foreignGenerate is a go:generate line running another generator.
+type foreignGenerate struct {
+	Comment *ast.Comment
+	// Tool is go-enum or enumer.
+	Tool string
+	Args []string
+}
+
+// Run prints the directives converted from each file, or rewrites the files
+// with --write.
+func (c *importCmd) Run(ctx *kong.Context) error {
+	for _, file := range c.File {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		generates := foreignGenerates(f)
+		enums, err := importEnums(f, generates)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if len(enums) == 0 {
+			return fmt.Errorf("no go-enum or enumer enums found in %s", file)
+		}
+
+		if !c.Write {
+			fmt.Fprintf(ctx.Stdout, "%s:\n", file)
+			for _, e := range enums {
+				for _, line := range e.Directive.format() {
+					fmt.Fprintln(ctx.Stdout, line)
+				}
+			}
+			continue
+		}
+		if err := rewriteImported(file, src, fset, enums, generates); err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx.Stdout, "%s: converted %d enums\n", file, len(enums))
+		for _, old := range foreignOutputs(file, generates) {
+			if _, err := os.Stat(old); err == nil {
+				fmt.Fprintf(ctx.Stdout, "%s: remove %s, whose code is now generated by go-safe-enum-generator\n", file, old)
+			}
+		}
+	}
+	return nil
+}
+
+// foreignGenerates returns the go:generate lines of f running go-enum or
+// enumer, directly or with go run.
+func foreignGenerates(f *ast.File) []foreignGenerate {
+	var generates []foreignGenerate
+	for _, group := range f.Comments {
+		for _, comment := range group.List {
+			fields := strings.Fields(strings.TrimPrefix(comment.Text, "//go:generate"))
+			if len(fields) == 0 || !strings.HasPrefix(comment.Text, "//go:generate ") {
+				continue
+			}
+			for i, field := range fields {
+				tool, _, _ := strings.Cut(path.Base(field), "@")
+				if tool == "go-enum" || tool == "enumer" {
+					generates = append(generates, foreignGenerate{Comment: comment, Tool: tool, Args: fields[i+1:]})
+					break
+				}
+			}
+		}
+	}
+	return generates
+}
+
+// flag returns the value of the named flag of the go:generate line (given as
+// -name=value, -name value or with two dashes), and whether it's set.
+func (g foreignGenerate) flag(name string) (string, bool) {
+	for i, arg := range g.Args {
+		key, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || key != name {
+			continue
+		}
+		if !hasValue && i+1 < len(g.Args) && !strings.HasPrefix(g.Args[i+1], "-") {
+			value = g.Args[i+1]
+		}
+		return value, true
+	}
+	return "", false
+}
+
+// importEnums returns the enums of f declared for the generators run by
+// generates: the types documented with an ENUM(...) list for go-enum, and
+// the types listed by -type with their constants for enumer.
+func importEnums(f *ast.File, generates []foreignGenerate) ([]foreignEnum, error) {
+	var enums []foreignEnum
+	for _, g := range generates {
+		switch g.Tool {
+		case "go-enum":
+			if _, ok := g.flag("noprefix"); ok {
+				logger.Warn("go-enum --noprefix isn't supported, the constants get the type name as prefix")
+			}
+			for _, decl := range f.Decls {
+				e, ok, err := importGoEnum(decl)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					enums = append(enums, e)
+				}
+			}
+		case "enumer":
+			types, _ := g.flag("type")
+			for _, name := range strings.Split(types, ",") {
+				if name == "" {
+					continue
+				}
+				e, err := importEnumer(f, name, g)
+				if err != nil {
+					return nil, err
+				}
+				enums = append(enums, e)
+			}
+		}
+	}
+	return enums, nil
+}
+
+// importGoEnum converts the type declared by decl if it's documented with a
+// go-enum ENUM(...) list.
+func importGoEnum(decl ast.Decl) (foreignEnum, bool, error) {
+	gd, ok := decl.(*ast.GenDecl)
+	if !ok || gd.Tok != token.TYPE || gd.Doc == nil {
+		return foreignEnum{}, false, nil
+	}
+	doc := gd.Doc.Text()
+	start := strings.Index(doc, "ENUM(")
+	if start < 0 {
+		return foreignEnum{}, false, nil
+	}
+	if len(gd.Specs) != 1 {
+		return foreignEnum{}, false, fmt.Errorf("the go-enum type must be declared alone, not in a type group")
+	}
+	spec := gd.Specs[0].(*ast.TypeSpec)
+	name := spec.Name.Name
+	list, _, ok := strings.Cut(doc[start+len("ENUM("):], ")")
+	if !ok {
+		return foreignEnum{}, false, fmt.Errorf("%s: unterminated ENUM(", name)
+	}
+
+	var values []valueInfo
+	for _, line := range strings.Split(list, "\n") {
+		line, comment, _ := strings.Cut(line, "//")
+		items := strings.Split(line, ",")
+		for i, item := range items {
+			item, explicit, hasValue := strings.Cut(item, "=")
+			item = strings.TrimSpace(item)
+			switch {
+			case item == "":
+				continue
+			case item == "_":
+				logger.Warn("go-enum skipped value dropped, the ints of the following values decrease", "enum", name)
+				continue
+			case hasValue:
+				logger.Warn("go-enum explicit value dropped, the ints follow the declaration order", "enum", name, "value", item, "int", strings.TrimSpace(explicit))
+			}
+			v := valueInfo{Original: item, GoName: sanitizeGoName(item)}
+			if i == len(items)-1 {
+				v.Doc = strings.TrimSpace(comment)
+			}
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return foreignEnum{}, false, fmt.Errorf("%s: no values in ENUM()", name)
+	}
+
+	options := "style=int"
+	if typ, ok := spec.Type.(*ast.Ident); ok && typ.Name == "string" {
+		options = "style=const"
+	}
+	return foreignEnum{
+		Directive: enumDirective{Name: name, Values: values, Options: options},
+		Decls:     []*ast.GenDecl{gd},
+	}, true, nil
+}
+
+// importEnumer converts the type name of f, listed by the enumer command g,
+// and its constants.
+func importEnumer(f *ast.File, name string, g foreignGenerate) (foreignEnum, error) {
+	e := foreignEnum{Directive: enumDirective{Name: name, Options: "style=int"}}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if ok && gd.Tok == token.TYPE && len(gd.Specs) == 1 && gd.Specs[0].(*ast.TypeSpec).Name.Name == name {
+			e.Decls = append([]*ast.GenDecl{gd}, e.Decls...)
+		}
+	}
+	if len(e.Decls) == 0 {
+		return e, fmt.Errorf("no declaration of the enumer type %s (declared alone, not in a type group)", name)
+	}
+
+	trimPrefixes, _ := g.flag("trimprefix")
+	transform, _ := g.flag("transform")
+	_, lineComment := g.flag("linecomment")
+	var consts []string
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		typ, others, found := "", false, false
+		for i, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			switch {
+			case vs.Type != nil:
+				typ = ""
+				if ident, ok := vs.Type.(*ast.Ident); ok {
+					typ = ident.Name
+				}
+			case len(vs.Values) > 0:
+				typ = ""
+			}
+			if typ != name {
+				others = true
+				continue
+			}
+			found = true
+			if len(vs.Values) > 0 {
+				if ident, ok := vs.Values[0].(*ast.Ident); i > 0 || !ok || ident.Name != "iota" {
+					logger.Warn("enumer explicit value dropped, the ints follow the declaration order", "enum", name, "value", vs.Names[0].Name)
+				}
+			}
+			for _, ident := range vs.Names {
+				if ident.Name == "_" {
+					logger.Warn("enumer skipped value dropped, the ints of the following values decrease", "enum", name)
+					continue
+				}
+				value := ident.Name
+				if lineComment && vs.Comment != nil {
+					value = strings.TrimSpace(vs.Comment.Text())
+				} else {
+					for _, prefix := range strings.Split(trimPrefixes, ",") {
+						if prefix != "" && strings.HasPrefix(value, prefix) {
+							value = value[len(prefix):]
+							break
+						}
+					}
+					var err error
+					if value, err = enumerTransform(value, transform); err != nil {
+						return e, err
+					}
+				}
+				consts = append(consts, ident.Name)
+				e.Directive.Values = append(e.Directive.Values, valueInfo{Original: value, GoName: sanitizeGoName(value)})
+			}
+		}
+		if !found {
+			continue
+		}
+		if others {
+			return e, fmt.Errorf("the constants of %s share their block with others (move them out first)", name)
+		}
+		e.Decls = append(e.Decls, gd)
+	}
+	if len(consts) == 0 {
+		return e, fmt.Errorf("no constants of the enumer type %s", name)
+	}
+
+	// keep the names of the constants, if the members aren't named alike
+	prefixed, bare := true, true
+	for i, v := range e.Directive.Values {
+		prefixed = prefixed && name+strings.Title(v.GoName) == consts[i]
+		bare = bare && v.GoName == consts[i]
+	}
+	switch {
+	case bare && !prefixed:
+		e.Directive.Options = "stringer"
+	case !prefixed:
+		logger.Warn("the constants are renamed, rename their uses", "enum", name, "example", consts[0]+" to "+name+strings.Title(e.Directive.Values[0].GoName))
+	}
+	if _, ok := g.flag("yaml"); ok {
+		e.Directive.Options += " yaml"
+	}
+	return e, nil
+}
+
+// enumerTransform returns name transformed like by the enumer -transform flag.
+func enumerTransform(name, transform string) (string, error) {
+	switch transform {
+	case "", "noop":
+		return name, nil
+	case "snake":
+		return strings.ToLower(screamingSnake(name)), nil
+	case "snake-upper":
+		return screamingSnake(name), nil
+	case "kebab":
+		return strings.ToLower(strings.ReplaceAll(screamingSnake(name), "_", "-")), nil
+	case "kebab-upper":
+		return strings.ReplaceAll(screamingSnake(name), "_", "-"), nil
+	case "lower":
+		return strings.ToLower(name), nil
+	case "upper":
+		return strings.ToUpper(name), nil
+	}
+	return "", fmt.Errorf("unsupported enumer transform %q (rename the values after importing)", transform)
+}
+
+// foreignOutputs returns the files generated by the generators of file, by
+// default or as given by their go:generate lines.
+func foreignOutputs(file string, generates []foreignGenerate) []string {
+	dir := filepath.Dir(file)
+	var outputs []string
+	for _, g := range generates {
+		if output, ok := g.flag("output"); ok {
+			outputs = append(outputs, filepath.Join(dir, output))
+			continue
+		}
+		if g.Tool == "go-enum" {
+			outputs = append(outputs, strings.TrimSuffix(file, ".go")+"_enum.go")
+			continue
+		}
+		types, _ := g.flag("type")
+		for _, name := range strings.Split(types, ",") {
+			outputs = append(outputs, filepath.Join(dir, strings.ToLower(name)+"_enumer.go"))
+		}
+	}
+	return outputs
+}
+
+// rewriteImported replaces the declarations of enums in file with their
+// directives, and the go:generate lines of the other generators with one
+// running this one, leaving the file unchanged if the result doesn't parse.
+func rewriteImported(file string, src []byte, fset *token.FileSet, enums []foreignEnum, generates []foreignGenerate) error {
+	offset := func(p token.Pos) int { return fset.Position(p).Offset }
+	var edits []symbolEdit
+	for _, e := range enums {
+		for i, decl := range e.Decls {
+			start := decl.Pos()
+			if decl.Doc != nil {
+				start = decl.Doc.Pos()
+			}
+			edit := symbolEdit{start: offset(start), end: offset(decl.End())}
+			if i == 0 {
+				edit.text = strings.Join(e.Directive.format(), "\n")
+			}
+			edits = append(edits, edit)
+		}
+	}
+	base := filepath.Base(file)
+	var lines []symbolEdit
+	for i, g := range generates {
+		edit := symbolEdit{start: offset(g.Comment.Pos()), end: offset(g.Comment.End())}
+		if i == 0 {
+			edit.text = fmt.Sprintf("//go:generate go-safe-enum-generator -f %s -o %s_enum_gen.go", base, strings.TrimSuffix(base, ".go"))
+		}
+		// a go:generate line in the doc of a declaration (as usual with
+		// enumer) is replaced along with the declaration
+		if d := docEdit(edits, edit); d != nil {
+			if edit.text != "" && d.text != "" {
+				d.text = edit.text + "\n\n" + d.text
+			} else if edit.text != "" {
+				d.text = edit.text
+			}
+			continue
+		}
+		lines = append(lines, edit)
+	}
+	edits = append(edits, lines...)
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for i := 1; i < len(edits); i++ {
+		if edits[i].end > edits[i-1].start {
+			return fmt.Errorf("%s: overlapping enum declarations and go:generate lines (move the go:generate lines out of the doc comments first)", file)
+		}
+	}
+	result := append([]byte{}, src...)
+	for _, edit := range edits {
+		result = append(result[:edit.start], append([]byte(edit.text), result[edit.end:]...)...)
+	}
+	formatted, err := format.Source(result)
+	if err != nil {
+		return fmt.Errorf("%s: formatting the converted file: %w", file, err)
+	}
+	if bytes.Equal(formatted, src) {
+		return nil
+	}
+
+	if err := writeOutput(file, formatted); err != nil {
+		return err
+	}
+	if _, err := loadFile(file, defaultGenOptions()); err != nil {
+		if restoreErr := writeOutput(file, src); restoreErr != nil {
+			return restoreErr
+		}
+		return fmt.Errorf("%s left unchanged: %w", file, err)
+	}
+	return nil
+}
+
+// docEdit returns the edit of decls replacing the bytes of edit, if any.
+func docEdit(decls []symbolEdit, edit symbolEdit) *symbolEdit {
+	for i := range decls {
+		if decls[i].start <= edit.start && edit.end <= decls[i].end {
+			return &decls[i]
+		}
+	}
+	return nil
+}
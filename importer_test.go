@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func TestImportWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "go-enum",
+			src: `package p
+
+//go:generate go-enum --marshal
+
+// Color is a color.
+// ENUM(red, green, blue)
+type Color int
+`,
+			want: `package p
+
+//go:generate go-safe-enum-generator -f types.go -o types_enum_gen.go
+
+// ENUM Color (red, green, blue) style=int
+`,
+		},
+		{
+			name: "enumer attached",
+			src: `package p
+
+//go:generate enumer -type=Level -trimprefix=Level
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+`,
+			want: `package p
+
+//go:generate go-safe-enum-generator -f types.go -o types_enum_gen.go
+
+// ENUM Level (Low, High) style=int
+`,
+		},
+		{
+			name: "enumer detached",
+			src: `package p
+
+//go:generate enumer -type=Level
+
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+`,
+			want: `package p
+
+//go:generate go-safe-enum-generator -f types.go -o types_enum_gen.go
+
+// ENUM Level (Low, High) stringer
+`,
+		},
+		{
+			name: "go-enum and enumer attached",
+			src: `package p
+
+//go:generate go-enum
+// ENUM(red, green)
+type Color int
+
+//go:generate enumer -type=Level -trimprefix=Level
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelHigh
+)
+`,
+			want: `package p
+
+//go:generate go-safe-enum-generator -f types.go -o types_enum_gen.go
+
+// ENUM Color (red, green) style=int
+
+// ENUM Level (Low, High) style=int
+`,
+		},
+		{
+			name: "type listed twice",
+			src: `package p
+
+//go:generate enumer -type=Level
+//go:generate enumer -type=Level -json
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+`,
+			wantErr: "overlapping enum declarations",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "types.go")
+			if err := os.WriteFile(file, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			cmd := importCmd{File: []string{file}, Write: true}
+			err := cmd.Run(&kong.Context{Kong: &kong.Kong{Stdout: &out}})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Run() = %v, want an error containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want
+			if tt.wantErr != "" {
+				want = tt.src
+			}
+			if string(got) != want {
+				t.Errorf("file after import:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/alecthomas/kong"
+)
+
+// lintCmd checks the style of the enum directives of files.
+type lintCmd struct {
+	File  []string `help:"File declaring the enums (repeatable)" short:"f" required:"" sep:"none"`
+	Rules []string `help:"Rules to check (sorted, duplicates, casing, descriptions)" enum:"sorted,duplicates,casing,descriptions" default:"duplicates,casing"`
+	Fix   bool     `help:"Rewrite the directives fixing the issues that can be (unsorted and duplicate values)"`
+	// FixUnsafe also sorts the values of the enums whose ints are stored.
+	FixUnsafe bool `help:"Like --fix, also sorting the values of int-style and append-only enums, changing their stored ints" name:"fix-unsafe"`
+}
+
+// lintIssue is a style issue of a directive.
+type lintIssue struct {
+	Rule    string
+	Message string
+	// Fixable is set when --fix rewrites the directive to solve the issue.
+	Fixable bool
+}
+
+// lintFix is how far the rules fix the issues they find.
+type lintFix int
+
+const (
+	lintReport lintFix = iota
+	// lintSafe fixes the issues without changing the ints stored for the
+	// enum.
+	lintSafe
+	// lintUnsafe fixes them all.
+	lintUnsafe
+)
+
+// lintTarget is a directive checked by the rules.
+type lintTarget struct {
+	*enumDirective
+	// KeepsInts is set when the ints of the values are stored rather than
+	// their strings, for int-style (or stringer) enums and with --append-only
+	// on the go:generate line of the file.
+	KeepsInts bool
+}
+
+// lintRules are the checks by name, returning the issues of a directive, and
+// fixing them in d as allowed by fix.
+var lintRules = map[string]func(d lintTarget, fix lintFix) []lintIssue{
+	"sorted":       lintSorted,
+	"duplicates":   lintDuplicates,
+	"casing":       lintCasing,
+	"descriptions": lintDescriptions,
+}
+
+// Run reports the issues of the directives of each file, fixing them with
+// --fix, and fails if some remain.
+func (c *lintCmd) Run(ctx *kong.Context) error {
+	fix := lintReport
+	switch {
+	case c.FixUnsafe:
+		fix = lintUnsafe
+	case c.Fix:
+		fix = lintSafe
+	}
+	remaining := 0
+	for _, file := range c.File {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(string(normalizeNewlines(data)), "\n")
+		directives, err := findDirectives(file, lines)
+		if err != nil {
+			return err
+		}
+		targets, err := lintTargets(file, lines, directives)
+		if err != nil {
+			return err
+		}
+
+		var fixed []enumDirective
+		for _, d := range targets {
+			var issues []lintIssue
+			for _, rule := range c.Rules {
+				issues = append(issues, lintRules[rule](d, fix)...)
+			}
+			changed := false
+			for _, issue := range issues {
+				if fix != lintReport && issue.Fixable {
+					changed = true
+					fmt.Fprintf(ctx.Stdout, "%s:%d: fixed: ENUM %s: %s (%s)\n", file, d.Start, d.Name, issue.Message, issue.Rule)
+					continue
+				}
+				remaining++
+				fmt.Fprintf(ctx.Stdout, "%s:%d: ENUM %s: %s (%s)\n", file, d.Start, d.Name, issue.Message, issue.Rule)
+			}
+			if changed {
+				fixed = append(fixed, *d.enumDirective)
+			}
+		}
+		if len(fixed) > 0 {
+			if err := writeDirectives(file, lines, fixed); err != nil {
+				return err
+			}
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("%d directive issues found", remaining)
+	}
+	return nil
+}
+
+// lintTargets returns the directives of the lines of file with the options
+// applying to them: the flags of the go:generate line of the file, those of
+// the namespace directive they follow and their own.
+func lintTargets(file string, lines []string, directives []enumDirective) ([]lintTarget, error) {
+	base := defaultGenOptions()
+	args, ok, err := generateLine(file, lines)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		gen, err := generateCommand(filepath.Dir(file), args)
+		if err != nil {
+			return nil, err
+		}
+		base = gen.options()
+	}
+
+	var targets []lintTarget
+	nsOpts := base
+	next := 0
+	for i, line := range lines {
+		if ns := namespaceRegex.FindStringSubmatch(line); ns != nil {
+			nsOpts = base
+			if ns[1] != "end" {
+				// invalid options are reported by the generation
+				_ = parseOptions(&nsOpts, ns[2])
+			}
+		}
+		if next < len(directives) && directives[next].Start == i+1 {
+			opts := nsOpts
+			_ = parseOptions(&opts, directives[next].Options)
+			targets = append(targets, lintTarget{
+				enumDirective: &directives[next],
+				KeepsInts:     opts.Style == "int" || opts.Stringer || opts.AppendOnly,
+			})
+			next++
+		}
+	}
+	return targets, nil
+}
+
+// lintSorted reports the values not in alphabetical order, ignoring case.
+// Sorting them changes their ints, so it's only fixed with lintUnsafe for the
+// enums whose ints are stored.
+func lintSorted(d lintTarget, fix lintFix) []lintIssue {
+	key := func(v valueInfo) string {
+		return strings.ToLower(strings.TrimPrefix(v.Original, "+"))
+	}
+	less := func(i, j int) bool {
+		return key(d.Values[i]) < key(d.Values[j])
+	}
+	if sort.SliceIsSorted(d.Values, less) {
+		return nil
+	}
+	if d.KeepsInts && fix != lintUnsafe {
+		return []lintIssue{{Rule: "sorted", Message: "values aren't sorted (sorting them changes their stored ints, use --fix-unsafe to sort them anyway)"}}
+	}
+	if fix != lintReport {
+		sort.SliceStable(d.Values, less)
+	}
+	return []lintIssue{{Rule: "sorted", Message: "values aren't sorted (sorting them changes their ints)", Fixable: true}}
+}
+
+// lintDuplicates reports the values declared more than once, ignoring case,
+// which are removed but for their first occurrence.
+func lintDuplicates(d lintTarget, fix lintFix) []lintIssue {
+	var issues []lintIssue
+	seen := map[string]bool{}
+	values := d.Values[:0:0]
+	for _, v := range d.Values {
+		key := strings.ToLower(strings.TrimPrefix(v.Original, "+"))
+		if seen[key] {
+			issues = append(issues, lintIssue{Rule: "duplicates", Message: fmt.Sprintf("value %q is declared more than once", v.Original), Fixable: true})
+			continue
+		}
+		seen[key] = true
+		values = append(values, v)
+	}
+	if fix != lintReport {
+		d.Values = values
+	}
+	return issues
+}
+
+// lintCasing reports the values whose casing (lower, UPPER, camelCase or
+// TitleCase) differs from the one of most values of the enum. Changing it
+// would change the strings of the values, so it isn't fixed.
+func lintCasing(d lintTarget, _ lintFix) []lintIssue {
+	counts := map[string]int{}
+	dominant := ""
+	for _, v := range d.Values {
+		casing := valueCasing(v.Original)
+		if casing == "" {
+			continue
+		}
+		counts[casing]++
+		if dominant == "" || counts[casing] > counts[dominant] {
+			dominant = casing
+		}
+	}
+	var issues []lintIssue
+	for _, v := range d.Values {
+		if casing := valueCasing(v.Original); casing != "" && casing != dominant {
+			issues = append(issues, lintIssue{Rule: "casing", Message: fmt.Sprintf("value %q is %s, most values are %s", v.Original, casing, dominant)})
+		}
+	}
+	return issues
+}
+
+// valueCasing returns the casing of the letters of s: "lower", "UPPER",
+// "camelCase" or "TitleCase", or "" if it has no cased letters.
+func valueCasing(s string) string {
+	hasLower, hasUpper, firstUpper, first := false, false, false, true
+	for _, r := range s {
+		if !unicode.IsUpper(r) && !unicode.IsLower(r) {
+			continue
+		}
+		if first {
+			firstUpper, first = unicode.IsUpper(r), false
+		}
+		hasLower = hasLower || unicode.IsLower(r)
+		hasUpper = hasUpper || unicode.IsUpper(r)
+	}
+	switch {
+	case hasLower && !hasUpper:
+		return "lower"
+	case hasUpper && !hasLower:
+		return "UPPER"
+	case hasUpper && firstUpper:
+		return "TitleCase"
+	case hasUpper:
+		return "camelCase"
+	}
+	return ""
+}
+
+// lintDescriptions reports the values without a # description.
+func lintDescriptions(d lintTarget, _ lintFix) []lintIssue {
+	var issues []lintIssue
+	for _, v := range d.Values {
+		if v.Doc == "" {
+			issues = append(issues, lintIssue{Rule: "descriptions", Message: fmt.Sprintf("value %q has no description", v.Original)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+func lintDirective(t *testing.T, text string) *enumDirective {
+	t.Helper()
+	directives, err := findDirectives("types.go", strings.Split(text, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(directives) != 1 {
+		t.Fatalf("found %d directives in %q", len(directives), text)
+	}
+	return &directives[0]
+}
+
+func issueMessages(issues []lintIssue) []string {
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+	return messages
+}
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		rule      string
+		directive string
+		want      []string
+	}{
+		{"sorted", "// ENUM Status (active, disabled)", nil},
+		{"sorted", "// ENUM Status (disabled, Active)", []string{"values aren't sorted (sorting them changes their ints)"}},
+		{"duplicates", "// ENUM Status (active, disabled)", nil},
+		{"duplicates", "// ENUM Status (active, disabled, Active)", []string{`value "Active" is declared more than once`}},
+		{"casing", "// ENUM Status (active, disabled, 404-not-found)", nil},
+		{"casing", "// ENUM Status (active, disabled, Pending)", []string{`value "Pending" is TitleCase, most values are lower`}},
+		{"descriptions", "// ENUM Status (\n// active # usable\n// )", nil},
+		{"descriptions", "// ENUM Status (\n// active # usable\n// disabled\n// )", []string{`value "disabled" has no description`}},
+	}
+	for _, tt := range tests {
+		d := lintDirective(t, tt.directive)
+		got := issueMessages(lintRules[tt.rule](lintTarget{enumDirective: d}, lintReport))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s on %s = %q, want %q", tt.rule, tt.directive, got, tt.want)
+		}
+	}
+}
+
+func TestLintSortedKeepsInts(t *testing.T) {
+	tests := []struct {
+		fix     lintFix
+		fixable bool
+		want    string
+	}{
+		{lintReport, false, "b, a"},
+		{lintSafe, false, "b, a"},
+		{lintUnsafe, true, "a, b"},
+	}
+	for _, tt := range tests {
+		d := lintDirective(t, "// ENUM Level (b, a) style=int")
+		issues := lintSorted(lintTarget{enumDirective: d, KeepsInts: true}, tt.fix)
+		if len(issues) != 1 || issues[0].Fixable != tt.fixable {
+			t.Errorf("lintSorted(%v) = %+v, want a single issue fixable: %v", tt.fix, issues, tt.fixable)
+		}
+		if got := d.format()[0]; !strings.Contains(got, "("+tt.want+")") {
+			t.Errorf("lintSorted(%v) left %s, want values (%s)", tt.fix, got, tt.want)
+		}
+	}
+}
+
+func TestLintTargets(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		keeps []bool
+	}{
+		{"defaults", "// ENUM A (x)\n// ENUM B (x) style=int\n// ENUM C (x) stringer\n", []bool{false, true, true}},
+		{"namespace", "// ENUM-NAMESPACE n style=int\n// ENUM A (x)\n// ENUM B (x) style=const\n// ENUM-NAMESPACE end\n// ENUM C (x)\n", []bool{true, false, false}},
+		{"go:generate", "//go:generate go-safe-enum-generator -f $GOFILE --append-only\n// ENUM A (x)\n", []bool{true}},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "types.go")
+		lines := strings.Split("package p\n\n"+tt.src, "\n")
+		directives, err := findDirectives(file, lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		targets, err := lintTargets(file, lines, directives)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var keeps []bool
+		for _, target := range targets {
+			keeps = append(keeps, target.KeepsInts)
+		}
+		if !reflect.DeepEqual(keeps, tt.keeps) {
+			t.Errorf("%s: KeepsInts = %v, want %v", tt.name, keeps, tt.keeps)
+		}
+	}
+}
+
+func TestLintFix(t *testing.T) {
+	const src = `package p
+
+// ENUM Status (pending, active, Active)
+
+// ENUM Level (low, high) style=int
+`
+	tests := []struct {
+		name      string
+		cmd       lintCmd
+		want      string
+		remaining bool
+	}{
+		{
+			name:      "report",
+			cmd:       lintCmd{Rules: []string{"sorted", "duplicates"}},
+			want:      src,
+			remaining: true,
+		},
+		{
+			name: "fix",
+			cmd:  lintCmd{Rules: []string{"sorted", "duplicates"}, Fix: true},
+			want: strings.Replace(src, "(pending, active, Active)", "(active, pending)", 1),
+			// the int-style Level isn't sorted
+			remaining: true,
+		},
+		{
+			name: "fix-unsafe",
+			cmd:  lintCmd{Rules: []string{"sorted", "duplicates"}, FixUnsafe: true},
+			want: strings.NewReplacer("(pending, active, Active)", "(active, pending)", "(low, high)", "(high, low)").Replace(src),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "types.go")
+			if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			tt.cmd.File = []string{file}
+			err := tt.cmd.Run(&kong.Context{Kong: &kong.Kong{Stdout: &out}})
+			if (err != nil) != tt.remaining {
+				t.Errorf("Run() = %v, want remaining issues: %v\n%s", err, tt.remaining, out.String())
+			}
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("file after lint:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintFixCRLF(t *testing.T) {
+	const src = "package p\r\n\r\n// ENUM Status (\r\n// pending # waiting\r\n// active\r\n// )\r\n\r\n// ENUM Level (low) style=int\r\n"
+	file := filepath.Join(t.TempDir(), "types.go")
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	cmd := lintCmd{File: []string{file}, Rules: []string{"sorted"}, Fix: true}
+	if err := cmd.Run(&kong.Context{Kong: &kong.Kong{Stdout: &out}}); err != nil {
+		t.Fatalf("Run() = %v\n%s", err, out.String())
+	}
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package p\r\n\r\n// ENUM Status (\r\n//   active,\r\n//   pending # waiting\r\n// )\r\n\r\n// ENUM Level (low) style=int\r\n"
+	if string(got) != want {
+		t.Errorf("file after lint = %q, want %q", got, want)
+	}
+}
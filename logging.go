@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+)
+
+// logger reports what the generator does. It's silent unless configured by
+// setupLogging.
+var logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// setupLogging makes logger write to w in format (text or json), at the info
+// level for a verbosity of 1 (-v) and the debug one from 2 (-vv).
+func setupLogging(w io.Writer, verbosity int, format string) {
+	level := slog.LevelWarn
+	switch {
+	case verbosity >= 2:
+		level = slog.LevelDebug
+	case verbosity == 1:
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		logger = slog.New(slog.NewJSONHandler(w, opts))
+		return
+	}
+	logger = slog.New(slog.NewTextHandler(w, opts))
+}
+
+// enabledFeatures returns the names of the options enabled for enum.
+func enabledFeatures(enum enumDef) []string {
+	features := []string{"style=" + enum.Style}
+	if enum.SQLBuilder != "" {
+		features = append(features, "sql-builder="+enum.SQLBuilder)
+	}
+	for _, f := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"yaml", enum.YAML},
+		{"env", enum.Env},
+		{"slugs", enum.Slugs},
+		{"csv", enum.CSV},
+		{"schema", enum.Schema},
+		{"strict", enum.Strict},
+		{"otel", enum.OTel},
+		{"prometheus", enum.Prom},
+		{"kubebuilder", enum.Kube},
+		{"terraform", enum.TF},
+		{"null-zero", enum.Null},
+		{"firestore", enum.Fire},
+		{"spanner", enum.Span},
+		{"clickhouse", enum.CH},
+		{"binary", enum.Binary},
+		{"protojson", enum.PJSON},
+		{"hash", enum.Hash},
+		{"list", enum.List},
+		{"set", enum.Set},
+		{"set-bitmask", enum.SetBitmask},
+		{"counts", enum.Counts},
+		{"fuzzy", enum.Fuzzy},
+		{"fixed-width", enum.Fixed},
+		{"packed", enum.Packed},
+		{"slices", enum.Slices},
+		{"swag", enum.Swag},
+		{"huma", enum.Huma},
+		{"select", enum.Select},
+		{"sorted", enum.Sorted},
+		{"elasticsearch", enum.ES},
+		{"mongo", enum.Mongo},
+		{"kafka", enum.Kafka},
+		{"feature-flag", enum.FeatureFlag},
+		{"stringer", enum.Stringer},
+		{"test-only", enum.TestOnly},
+		{"shared-helpers", enum.SharedHelpers},
+		{"minimal", enum.Minimal},
+	} {
+		if f.enabled {
+			features = append(features, f.name)
+		}
+	}
+	return features
+}
@@ -1,41 +1,702 @@
 package main
 
 import (
-	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"go/parser"
 	"go/token"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
-	"text/template"
+	"sync"
 
 	"github.com/alecthomas/kong"
 )
 
 var CLI struct {
-	File   string `help:"Input file to process" short:"f" required:""`
-	Output string `help:"Output file (defaults to stdout)" short:"o"`
-	YAML   bool   `help:"Generate YAML marshaler/unmarshaler" short:"y"`
+	Verbose   int              `help:"Log the scanned files, the enums found and their options on stderr (-vv for more details)" short:"v" type:"counter"`
+	LogFormat string           `help:"Format of the logs (text, json)" enum:"text,json" default:"text"`
+	Version   kong.VersionFlag `help:"Print the version of the generator"`
+
+	Generate generateCmd `cmd:"" default:"withargs" help:"Generate the enums declared in a file (default command)"`
+	Compat   compatCmd   `cmd:"" help:"Report breaking changes of the enums declared in a file against a previous version"`
+	New      newCmd      `cmd:"" help:"Add an enum directive to a file"`
+	Edit     editCmd     `cmd:"" help:"Interactively edit the enums declared in a file"`
+	Lint     lintCmd     `cmd:"" help:"Check the style of the enum directives of files"`
+	Import   importCmd   `cmd:"" help:"Convert the enums declared for go-enum and enumer to directives"`
+
+	TemplateSchema templateSchemaCmd `cmd:"" help:"Print the data and functions available to custom templates"`
+	Template       templateCmd       `cmd:"" help:"Work with custom templates"`
+}
+
+// generateCmd generates the code of the enums declared in files.
+type generateCmd struct {
+	File        []string `help:"Input file to process (repeatable, -o then being a directory)" short:"f" required:"" sep:"none"`
+	Output      string   `help:"Output file (defaults to stdout), or directory with several input files (defaults to their own)" short:"o"`
+	PackageName string   `help:"Package of the generated code, instead of the one of the input file (which then doesn't need to be valid Go)"`
+
+	YAML        bool   `help:"Generate YAML marshaler/unmarshaler" short:"y"`
+	Env         bool   `help:"Generate env parsing helpers (caarlos0/env, envconfig)" short:"e"`
+	Style       string `help:"Code style of the generated enums (struct, const, int)" enum:"struct,const,int" default:"struct"`
+	Slugs       bool   `help:"Also generate raw string constants for each value (<Member>Slug)"`
+	CSV         bool   `help:"Generate helpers converting enum slices to and from separated lists"`
+	Schema      bool   `help:"Generate the gorilla/schema converter (use --no-schema to drop it and the reflect import)" default:"true" negatable:""`
+	Strict      bool   `help:"Reject empty and unknown values when unmarshaling JSON and YAML, leaving the receiver untouched"`
+	OTel        bool   `help:"Generate OpenTelemetry attribute helpers" name:"otel"`
+	Prom        bool   `help:"Generate Prometheus label helpers" name:"prometheus"`
+	Kube        bool   `help:"Generate kubebuilder validation markers and DeepCopy methods for CRD types" name:"kubebuilder"`
+	TF          bool   `help:"Generate terraform-plugin-framework schema validators" name:"terraform"`
+	Null        bool   `help:"Marshal the zero value of struct and const enums to JSON null (and generate IsZero for omitzero)" name:"null-zero"`
+	Fire        bool   `help:"Generate Firestore and Datastore property conversion helpers" name:"firestore"`
+	Span        bool   `help:"Generate Cloud Spanner Encoder and Decoder implementations" name:"spanner"`
+	CH          bool   `help:"Generate the ClickHouse Enum8/Enum16 column type of the enums" name:"clickhouse"`
+	Binary      bool   `help:"Generate compact binary marshalers (for go-redis and other caches)"`
+	PJSON       bool   `help:"Also parse the protobuf JSON (SCREAMING_SNAKE_CASE) names of the values" name:"protojson"`
+	Hash        bool   `help:"Generate stable 32-bit hashes of the values (of their uuid attributes, if declared) with reverse lookups"`
+	List        bool   `help:"Generate a <Name>List slice type with helpers, JSON marshaling and database/sql support"`
+	Set         bool   `help:"Generate a <Name>Set type (up to 64 values) marshaled as a JSON array"`
+	SetBM       bool   `help:"Store the <Name>Set types in integer columns as bitmasks (implies --set)" name:"set-bitmask"`
+	Counts      bool   `help:"Generate a <Name>Counts type counting values in an array, safe for concurrent use"`
+	Fuzzy       bool   `help:"Generate ParseFuzzy, tolerating case, separators and typos"`
+	Fixed       bool   `help:"Generate EncodeTo and DecodeFrom, encoding the values in 1 or 2 bytes" name:"fixed-width"`
+	Packed      bool   `help:"Generate Pack<Name>s and Unpack<Name>s, bit-packing enum slices"`
+	Slices      bool   `help:"Generate Compare<Name> and the Sort, BinarySearch and Compact functions of enum slices in declaration order"`
+	Swag        bool   `help:"Generate the list of the values for the enums struct tag and Enums attribute of swaggo/swag"`
+	Huma        bool   `help:"Generate huma.SchemaProvider implementations publishing the values in OpenAPI schemas"`
+	Select      bool   `help:"Generate the value and label pairs of <select> controls, usable from html/template"`
+	Sorted      bool   `help:"Declare the members in alphabetical order, their ints still following the declaration order (or the previous output with --append-only)"`
+	ES          bool   `help:"Generate the Elasticsearch/OpenSearch keyword mapping of the enums and terms query helpers" name:"elasticsearch"`
+	Mongo       bool   `help:"Generate MongoDB filter helpers (<Name>In, <Name>Nin) rejecting invalid values"`
+	Kafka       bool   `help:"Generate the helpers carrying the enums in Kafka message headers"`
+	FeatureFlag bool   `help:"Generate the variants of feature flags evaluated as the enums, and the parsing of their evaluations" name:"feature-flag"`
+	Stringer    bool   `help:"Use the int style, also declaring the values under their own names, as the constants stringer was run on"`
+
+	CSVSeparator string `help:"Separator used by the CSV helpers" default:","`
+	OnParseError string `help:"What a failed Parse leaves in the receiver (keep, zero, first)" enum:"keep,zero,first" default:"keep"`
+	ZeroString   string `help:"What String returns for the zero value of struct and const enums (empty, invalid, default)" enum:"empty,invalid,default" default:"empty"`
+	SQLBuilder   string `help:"Generate the Where<Name> and <Name>Eq helpers of a SQL query builder (squirrel, goqu)" enum:",squirrel,goqu" default:""`
+	VarNames     string `help:"Naming of the unexported variables generated for each enum (lower: authtypeValues, namespaced: _enumAuthTypeValues)" enum:"lower,namespaced" default:"lower"`
+	AvroDir      string `help:"Directory to write an Avro schema (<Name>.avsc) for each enum to" type:"path"`
+	AvroNS       string `help:"Namespace of the Avro schemas (defaults to the package name)" name:"avro-namespace"`
+	ThriftOut    string `help:"Thrift file to write the enums to" type:"path"`
+	FBSOut       string `help:"FlatBuffers schema file to write the enums to" type:"path" name:"fbs-out"`
+	GraphQLOut   string `help:"GraphQL schema file to write the enums to" type:"path" name:"graphql-out"`
+	FormsOut     string `help:"JSON file (or TypeScript module, if ending with .ts) to write the form options and JSON Forms schemas of the enums to" type:"path" name:"forms-out"`
+	DepsOut      string `help:"File to write the go.mod require block of the modules needed by the generated code to" type:"path" name:"deps-out"`
+	Template     string `help:"Custom template to render with the enums (see the template-schema command)" type:"existingfile"`
+	TemplateOut  string `help:"File to write the rendered custom template to (defaults to stdout)" type:"path"`
+	Translations string `help:"JSON file with the display names of the values by enum and language, generating ParseLocalized and Label" type:"existingfile"`
+	Changelog    string `help:"Markdown file to write the enum changes since the --changelog-against git revision to" type:"path"`
+	ChangelogRef string `help:"Git revision the changelog is computed against" name:"changelog-against" default:"HEAD"`
+	MigrationDir string `help:"Directory to write a PostgreSQL migration for the types and values added since the previous output to" type:"path" name:"migrations-dir"`
+	MigrationFmt string `help:"Format of the migrations (goose, atlas)" enum:"goose,atlas" default:"goose" name:"migrations-format"`
+	MinGo        string `help:"Minimum Go version of the target module, enabling newer stdlib interfaces (defaults to the go.mod one)"`
+	MaxLineLen   int    `help:"Maximum length in bytes of the lines of the input files" default:"1048576" name:"max-line-length"`
+
+	SharedHelpers bool `help:"Factor common logic into a shared generic helpers file (enum_helpers_gen.go)"`
+	GenGolden     bool `help:"Generate a test checking slugs and int mappings against golden files"`
+	GenRoundtrip  bool `help:"Generate a test checking that every member survives its string, JSON, YAML, text and database/sql round trips"`
+	Minimal       bool `help:"Only generate the type, its members, String, Parse and IsValid (no database/sql, encoding/json or reflect)"`
+	AppendOnly    bool `help:"Keep the order (and ints) of the members already in the output file, appending the new ones"`
+	Check         bool `help:"Write nothing, failing if the output file isn't up to date"`
+	FieldDocs     bool `help:"Add or refresh the allowed values in the doc comments of the struct fields typed as the enums, in the package of the input file"`
+	FieldConsts   bool `help:"Generate the JSON names of the struct fields typed as the enums, in the package of the input file, with functions pairing them with values for query builders (into <output>_fields.go)"`
+	Cache         bool `help:"Skip the input files whose directives and options didn't change since their outputs were generated" env:"SAFE_ENUM_CACHE"`
+	Hermetic      bool `help:"Only read the input files and write the outputs given (no go.mod lookup, no scan of the package, no cache), for Bazel and other hermetic builds"`
+	ListDeps      bool `help:"Write nothing but the import paths, outside of the standard library, of the generated code" name:"listdeps"`
+
+	CacheDir string `help:"Directory of the generation cache (defaults to go-safe-enum-generator in the user cache directory)" type:"path" env:"SAFE_ENUM_CACHE_DIR"`
+
+	Plugin []string `help:"Plugin to run on the enums, as name[=parameter] (repeatable, see the README for the protocol)" sep:"none"`
+
+	ErrorFormat string `help:"Format of the errors (text, or json for one diagnostic object per line on stdout)" enum:"text,json" default:"text"`
+
+	VerifyDeterminism bool `help:"Render twice and fail if the outputs differ" hidden:""`
+}
+
+type genOptions struct {
+	YAML        bool
+	Env         bool
+	Style       string
+	Slugs       bool
+	CSV         bool
+	Schema      bool
+	Strict      bool
+	OTel        bool
+	Prom        bool
+	Kube        bool
+	TF          bool
+	Null        bool
+	Fire        bool
+	Span        bool
+	CH          bool
+	Binary      bool
+	PJSON       bool
+	Hash        bool
+	List        bool
+	Set         bool
+	SetBM       bool
+	Counts      bool
+	Fuzzy       bool
+	Fixed       bool
+	Packed      bool
+	Slices      bool
+	Swag        bool
+	Huma        bool
+	Select      bool
+	Sorted      bool
+	ES          bool
+	Mongo       bool
+	Kafka       bool
+	FeatureFlag bool
+	Stringer    bool
+
+	CSVSeparator string
+	OnParseError string
+	ZeroString   string
+	SQLBuilder   string
+	VarNames     string
+	AvroDir      string
+	AvroNS       string
+	ThriftOut    string
+	FBSOut       string
+	GraphQLOut   string
+	FormsOut     string
+	DepsOut      string
+	Template     string
+	TemplateOut  string
+	Translations string
+	Plugins      []string
+	Changelog    string
+	ChangelogRef string
+	MigrationDir string
+	MigrationFmt string
+	MinGo        string
+	MaxLineLen   int
+	PackageName  string
+	// Skip and Rename are the comma separated symbols to skip and old:new
+	// pairs of functions to rename, set by directive options only.
+	Skip, Rename string
+	// TestOnly is set by the test-only directive option only.
+	TestOnly bool
+	// Out is the file name set by the out directive option only.
+	Out string
+
+	SharedHelpers bool
+	GenGolden     bool
+	GenRoundtrip  bool
+	Minimal       bool
+	AppendOnly    bool
+	Check         bool
+	FieldDocs     bool
+	FieldConsts   bool
+	Hermetic      bool
+	ListDeps      bool
+
+	VerifyDeterminism bool
+}
+
+// fileDef holds everything declared by the directives of an input file.
+type fileDef struct {
+	Package  string
+	Enums    []enumDef
+	Mappings []enumMapping
+	// Protos are the conversions of the enums wrapping protobuf enums.
+	Protos []protoEnum
 }
 
 type valueInfo struct {
 	Original string
 	GoName   string
+	Doc      string
+	// Attrs are the attributes declared in brackets after the value.
+	Attrs map[string]string
 }
 
 type enumDef struct {
-	Package string
-	Name    string
-	Values  []valueInfo
-	YAML    bool
+	Package   string
+	Namespace string
+	Name      string
+	Values    []valueInfo
+	YAML      bool
+	Env       bool
+	Style     string
+	Slugs     bool
+	CSV       bool
+	Schema    bool
+	Strict    bool
+	OTel      bool
+	Prom      bool
+	Kube      bool
+	TF        bool
+	Null      bool
+	Fire      bool
+	Span      bool
+	CH        bool
+	Binary    bool
+	PJSON     bool
+	Hash      bool
+	List      bool
+	Set       bool
+	// SetBitmask is set when the sets are stored as bitmasks.
+	SetBitmask  bool
+	Counts      bool
+	Fuzzy       bool
+	Fixed       bool
+	Packed      bool
+	Slices      bool
+	Swag        bool
+	Huma        bool
+	Select      bool
+	Sorted      bool
+	ES          bool
+	Mongo       bool
+	Kafka       bool
+	FeatureFlag bool
+	Stringer    bool
+	// GraphQL is set when the enums are written as GraphQL SDL, to be bound
+	// by gqlgen.
+	GraphQL bool
+
+	CSVSeparator  string
+	OnParseError  string
+	ZeroString    string
+	SQLBuilder    string
+	VarNames      string
+	AvroNamespace string
+	MinGo         string
+	// ProtoPrefix is the prefix of the protobuf names of the values.
+	ProtoPrefix string
+
+	SharedHelpers bool
+	Minimal       bool
+
+	// Extends is the base enum this one is a superset of, if any.
+	Extends *enumDef
+	// SubsetOf is the parent enum this one is a subset of, if any.
+	SubsetOf *enumDef
+	// Labels are the display names of the values by language and value, if
+	// translated.
+	Labels map[string]map[string]string
+	// Skip are the generated functions and methods to remove, and Rename the
+	// new names of the generated functions, by old name.
+	Skip   []string
+	Rename map[string]string
+	// TestOnly is set when the enum is generated into the test file of the
+	// output.
+	TestOnly bool
+	// Out is the name of the file the enum is generated into, next to the
+	// output, if not the output.
+	Out string
+}
+
+func newEnumDef(pkgName, namespace, name string, values []valueInfo, opts genOptions) enumDef {
+	// the options were validated by parseOptions
+	var skip []string
+	var rename map[string]string
+	if opts.Skip != "" {
+		skip, _ = parseSymbolList(opts.Skip)
+	}
+	if opts.Rename != "" {
+		rename, _ = parseRenames(opts.Rename)
+	}
+	if opts.Stringer {
+		// the values are declared as the constants of an int type, like for stringer
+		opts.Style = "int"
+	}
+	if opts.Minimal {
+		// minimal mode wins over the options pulling in other imports
+		opts.YAML, opts.Env, opts.CSV, opts.Schema = false, false, false, false
+		opts.Strict, opts.OTel, opts.Prom, opts.Kube, opts.TF, opts.Null = false, false, false, false, false, false
+		opts.Fire, opts.Span, opts.Binary, opts.Hash, opts.List = false, false, false, false, false
+		opts.Set, opts.SetBM, opts.Counts, opts.Fuzzy, opts.Fixed, opts.Packed = false, false, false, false, false, false
+		opts.Slices, opts.Huma, opts.Mongo, opts.Kafka, opts.FeatureFlag, opts.SQLBuilder = false, false, false, false, false, ""
+	}
+	return enumDef{
+		Package:   pkgName,
+		Namespace: namespace,
+		Name:      name,
+		Values:    values,
+		YAML:      opts.YAML,
+		Env:       opts.Env,
+		Style:     opts.Style,
+		Slugs:     opts.Slugs,
+		CSV:       opts.CSV,
+		Schema:    opts.Schema,
+		Strict:    opts.Strict,
+		OTel:      opts.OTel,
+		Prom:      opts.Prom,
+		Kube:      opts.Kube,
+		TF:        opts.TF,
+		Null:      opts.Null && opts.Style != "int",
+		Fire:      opts.Fire,
+		Span:      opts.Span,
+		CH:        opts.CH,
+		Binary:    opts.Binary,
+		PJSON:     opts.PJSON,
+		Hash:      opts.Hash,
+		List:      opts.List,
+		Set:       opts.Set || opts.SetBM,
+
+		SetBitmask:  opts.SetBM,
+		Counts:      opts.Counts,
+		Fuzzy:       opts.Fuzzy,
+		Fixed:       opts.Fixed,
+		Packed:      opts.Packed,
+		Slices:      opts.Slices,
+		Swag:        opts.Swag,
+		Huma:        opts.Huma,
+		Select:      opts.Select,
+		Sorted:      opts.Sorted,
+		ES:          opts.ES,
+		Mongo:       opts.Mongo,
+		Kafka:       opts.Kafka,
+		FeatureFlag: opts.FeatureFlag,
+		Stringer:    opts.Stringer,
+		GraphQL:     opts.GraphQLOut != "" && !opts.Minimal,
+
+		CSVSeparator:  opts.CSVSeparator,
+		OnParseError:  opts.OnParseError,
+		ZeroString:    opts.ZeroString,
+		SQLBuilder:    opts.SQLBuilder,
+		VarNames:      opts.VarNames,
+		AvroNamespace: opts.AvroNS,
+		MinGo:         opts.MinGo,
+		ProtoPrefix:   screamingSnake(name) + "_",
+
+		SharedHelpers: opts.SharedHelpers,
+		Minimal:       opts.Minimal,
+
+		Skip:   skip,
+		Rename: rename,
+
+		TestOnly: opts.TestOnly,
+		Out:      opts.Out,
+	}
+}
+
+// VarPrefix returns the prefix of the unexported package-level variables and
+// functions generated for the enum: its name preceded by _enum with the
+// namespaced var-names, otherwise its name in lower case, or preceded by an
+// underscore for unexported enums, whose name would collide with the exported
+// functions prefixed by it (colorValues).
+func (e enumDef) VarPrefix() string {
+	if e.VarNames == "namespaced" {
+		return "_enum" + e.Name
+	}
+	if !token.IsExported(e.Name) {
+		return "_" + e.Name
+	}
+	return strings.ToLower(e.Name)
+}
+
+// Prefixed returns the name of a function starting with prefix followed by
+// the enum name, unexported for unexported enums (newColorSet for color).
+func (e enumDef) Prefixed(prefix string) string {
+	if !token.IsExported(e.Name) {
+		return strings.ToLower(prefix[:1]) + prefix[1:] + strings.Title(e.Name)
+	}
+	return prefix + e.Name
+}
+
+// Raw returns the expression of the slug held by the variable recv, for the
+// encoders: unlike String, it ignores the zero-string option, so that the
+// zero value is stored as an empty string and can be read back.
+func (e enumDef) Raw(recv string) string {
+	switch {
+	case e.ZeroString == "empty" || e.Style == "int":
+		return recv + ".String()"
+	case e.Style == "const":
+		return "string(" + recv + ")"
+	default:
+		return recv + ".slug"
+	}
+}
+
+// ValueList returns the comma separated list of the original values.
+func (e enumDef) ValueList() string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = v.Original
+	}
+	return strings.Join(values, ", ")
+}
+
+// lookupEntry is a key of the case-insensitive Parse lookup table.
+type lookupEntry struct {
+	Key   string
+	Value valueInfo
+}
+
+// LookupEntries returns the keys Parse accepts (in lower case) with their
+// values: the original values and, with the protojson option, the protobuf
+// JSON names, with and without prefix, unless they collide with other keys.
+func (e enumDef) LookupEntries() []lookupEntry {
+	var entries []lookupEntry
+	seen := map[string]bool{}
+	add := func(key string, v valueInfo) {
+		key = strings.ToLower(key)
+		if !seen[key] {
+			seen[key] = true
+			entries = append(entries, lookupEntry{key, v})
+		}
+	}
+	for _, v := range e.Values {
+		add(v.Original, v)
+	}
+	if e.PJSON {
+		for _, v := range e.Values {
+			add(e.ProtoJSONName(v), v)
+			add(strings.TrimPrefix(e.ProtoJSONName(v), e.ProtoPrefix), v)
+		}
+	}
+	return entries
+}
+
+// SortedValues returns the values in the order the members are declared in:
+// alphabetical with the sorted option, the one of their ints otherwise.
+func (e enumDef) SortedValues() []valueInfo {
+	if !e.Sorted {
+		return e.Values
+	}
+	values := append([]valueInfo{}, e.Values...)
+	sort.SliceStable(values, func(i, j int) bool {
+		return strings.ToLower(values[i].Original) < strings.ToLower(values[j].Original)
+	})
+	return values
+}
+
+// IntOf returns the int of v.
+func (e enumDef) IntOf(v valueInfo) int {
+	for i, value := range e.Values {
+		if value.Original == v.Original {
+			return i
+		}
+	}
+	return -1
+}
+
+// HasDocs reports whether at least one of the values is documented.
+func (e enumDef) HasDocs() bool {
+	for _, v := range e.Values {
+		if v.Doc != "" {
+			return true
+		}
+	}
+	return false
 }
 
 func main() {
-	ctx := kong.Parse(&CLI)
-	if err := processFile(CLI.File, CLI.Output, CLI.YAML); err != nil {
-		ctx.FatalIfErrorf(err)
+	if strings.HasPrefix(filepath.Base(os.Args[0]), protocPluginPrefix) {
+		runProtocPlugin(defaultGenOptions())
+		return
+	}
+
+	ctx := kong.Parse(&CLI, kong.Vars{"version": versionString()})
+	setupLogging(os.Stderr, CLI.Verbose, CLI.LogFormat)
+	ctx.FatalIfErrorf(ctx.Run())
+}
+
+// defaultGenOptions returns the options used when no flag is given.
+func defaultGenOptions() genOptions {
+	return genOptions{Style: "struct", Schema: true, CSVSeparator: ",", OnParseError: "keep", ZeroString: "empty", VarNames: "lower"}
+}
+
+// Run generates the enums of each input file.
+func (c *generateCmd) Run(ctx *kong.Context) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	regenerated, unchanged := 0, 0
+	for _, file := range c.File {
+		cached, err := c.generate(file, c.outputOf(file))
+		if err == nil {
+			if cached {
+				unchanged++
+			} else {
+				regenerated++
+			}
+			continue
+		}
+		if c.ErrorFormat == "json" {
+			if err := writeJSONDiagnostic(ctx.Stdout, file, err); err != nil {
+				return err
+			}
+			ctx.Exit(exitCode(err))
+		}
+		var d *diagnostic
+		if len(c.File) > 1 && (!errors.As(err, &d) || d.Line == 0) {
+			err = fmt.Errorf("%s: %w", file, err)
+		}
+		ctx.Errorf("%s", err)
+		ctx.Exit(exitCode(err))
+		return err
+	}
+	if c.Cache && !c.Check && !c.ListDeps {
+		fmt.Fprintf(ctx.Stderr, "%d files regenerated, %d unchanged\n", regenerated, unchanged)
+	}
+	return nil
+}
+
+// validate checks the combinations of flags.
+func (c *generateCmd) validate() error {
+	if len(c.File) > 1 && (c.ThriftOut != "" || c.FBSOut != "" || c.GraphQLOut != "" || c.FormsOut != "" || c.DepsOut != "" || c.Template != "" || c.Changelog != "" || c.MigrationDir != "") {
+		return errors.New("--thrift-out, --fbs-out, --graphql-out, --forms-out, --deps-out, --template, --changelog and --migrations-dir can't be used with several input files")
+	}
+	if c.Hermetic && (c.Cache || c.FieldDocs || c.FieldConsts || c.Changelog != "") {
+		return errors.New("--hermetic can't be combined with --cache, --field-docs, --field-consts or --changelog")
+	}
+	if c.Hermetic && len(c.File) > 1 && c.Output == "" && !c.ListDeps {
+		return errors.New("--hermetic requires an -o directory with several input files")
+	}
+	return nil
+}
+
+// outputOf returns the output file of the input file: the -o one when there
+// is a single input, otherwise <file>_enum_gen.go in the -o directory (or
+// next to the input).
+func (c *generateCmd) outputOf(file string) string {
+	if len(c.File) == 1 {
+		return c.Output
+	}
+	dir := filepath.Dir(file)
+	if c.Output != "" {
+		dir = c.Output
+	}
+	return filepath.Join(dir, strings.TrimSuffix(filepath.Base(file), ".go")+"_enum_gen.go")
+}
+
+// generate generates the enums of file into output, reporting whether it was
+// skipped as unchanged since the cached generation.
+func (c *generateCmd) generate(file, output string) (cached bool, err error) {
+	opts := c.options()
+	if c.Minimal && (c.SharedHelpers || c.GenGolden) {
+		return false, errors.New("--minimal can't be combined with --shared-helpers or --gen-golden")
+	}
+	if c.PackageName != "" && !token.IsIdentifier(c.PackageName) {
+		return false, fmt.Errorf("invalid package name %q", c.PackageName)
+	}
+	modFile := file
+	if c.Hermetic {
+		// only --min-go is used
+		modFile = ""
+	}
+	minGo, err := minGoVersion(c.MinGo, modFile)
+	if err != nil {
+		return false, err
+	}
+	opts.MinGo = minGo
+	// the plugins, the changelog and the field docs and constants depend on
+	// more than the directives
+	if !c.Cache || c.Check || c.ListDeps || output == "" || len(c.Plugin) > 0 || c.Changelog != "" || c.FieldDocs || c.FieldConsts {
+		return false, processFile(file, output, opts)
+	}
+
+	cache, err := openCache(c.CacheDir)
+	if err != nil {
+		return false, err
+	}
+	def, err := loadOrderedFile(file, output, opts)
+	if err != nil {
+		return false, err
+	}
+	key, err := generationKey(def, output, opts)
+	if err != nil {
+		return false, err
+	}
+	if cache.fresh(file, output, key) {
+		logger.Info("skipping unchanged file", "file", file)
+		return true, nil
+	}
+	if err := processFile(file, output, opts); err != nil {
+		return false, err
+	}
+	return false, cache.store(file, output, key, generatedFiles(def, file, output, opts)...)
+}
+
+// options returns the generation options given by the flags.
+func (c *generateCmd) options() genOptions {
+	return genOptions{
+		YAML:        c.YAML,
+		Env:         c.Env,
+		Style:       c.Style,
+		Slugs:       c.Slugs,
+		CSV:         c.CSV,
+		Schema:      c.Schema,
+		Strict:      c.Strict,
+		OTel:        c.OTel,
+		Prom:        c.Prom,
+		Kube:        c.Kube,
+		TF:          c.TF,
+		Null:        c.Null,
+		Fire:        c.Fire,
+		Span:        c.Span,
+		CH:          c.CH,
+		Binary:      c.Binary,
+		PJSON:       c.PJSON,
+		Hash:        c.Hash,
+		List:        c.List,
+		Set:         c.Set,
+		SetBM:       c.SetBM,
+		Counts:      c.Counts,
+		Fuzzy:       c.Fuzzy,
+		Fixed:       c.Fixed,
+		Packed:      c.Packed,
+		Slices:      c.Slices,
+		Swag:        c.Swag,
+		Huma:        c.Huma,
+		Select:      c.Select,
+		Sorted:      c.Sorted,
+		ES:          c.ES,
+		Mongo:       c.Mongo,
+		Kafka:       c.Kafka,
+		FeatureFlag: c.FeatureFlag,
+		Stringer:    c.Stringer,
+
+		CSVSeparator: c.CSVSeparator,
+		OnParseError: c.OnParseError,
+		ZeroString:   c.ZeroString,
+		SQLBuilder:   c.SQLBuilder,
+		VarNames:     c.VarNames,
+		AvroDir:      c.AvroDir,
+		AvroNS:       c.AvroNS,
+		ThriftOut:    c.ThriftOut,
+		FBSOut:       c.FBSOut,
+		GraphQLOut:   c.GraphQLOut,
+		FormsOut:     c.FormsOut,
+		DepsOut:      c.DepsOut,
+		Template:     c.Template,
+		TemplateOut:  c.TemplateOut,
+		Translations: c.Translations,
+		Plugins:      c.Plugin,
+		Changelog:    c.Changelog,
+		ChangelogRef: c.ChangelogRef,
+		MigrationDir: c.MigrationDir,
+		MigrationFmt: c.MigrationFmt,
+		MinGo:        c.MinGo,
+		MaxLineLen:   c.MaxLineLen,
+		PackageName:  c.PackageName,
+
+		SharedHelpers: c.SharedHelpers,
+		GenGolden:     c.GenGolden,
+		GenRoundtrip:  c.GenRoundtrip,
+		Minimal:       c.Minimal,
+		AppendOnly:    c.AppendOnly,
+		Check:         c.Check,
+		FieldDocs:     c.FieldDocs,
+		FieldConsts:   c.FieldConsts,
+		Hermetic:      c.Hermetic,
+		ListDeps:      c.ListDeps,
+
+		VerifyDeterminism: c.VerifyDeterminism,
 	}
 }
 
@@ -71,281 +732,525 @@ func sanitizeGoName(s string) string {
 	return safe
 }
 
-func processFile(filename, output string, yaml bool) error {
-	pkgName, err := getPackageName(filename)
-	if err != nil {
-		return fmt.Errorf("getting package name: %w", err)
+// nameTable returns the Go string literal concatenating all the original
+// values, used by the int style to look up names without a slice of strings.
+func nameTable(values []valueInfo) string {
+	var sb strings.Builder
+	for _, v := range values {
+		sb.WriteString(v.Original)
 	}
+	return strconv.Quote(sb.String())
+}
 
-	file, err := os.Open(filename)
-	if err != nil {
-		return fmt.Errorf("opening file: %w", err)
+// nameIndex returns the offsets of each value in the nameTable string.
+func nameIndex(values []valueInfo) string {
+	offsets := make([]string, 0, len(values)+1)
+	offset := 0
+	offsets = append(offsets, "0")
+	for _, v := range values {
+		offset += len(v.Original)
+		offsets = append(offsets, strconv.Itoa(offset))
 	}
-	defer file.Close()
+	return strings.Join(offsets, ", ")
+}
 
-	var out io.Writer
-	if output == "" {
-		out = os.Stdout
-	} else {
-		f, err := os.Create(output)
-		if err != nil {
-			return fmt.Errorf("creating output file: %w", err)
-		}
-		defer f.Close()
-		out = f
+// nameIndexType returns the smallest unsigned integer type able to hold the
+// offsets returned by nameIndex.
+func nameIndexType(values []valueInfo) string {
+	total := 0
+	for _, v := range values {
+		total += len(v.Original)
+	}
+	switch {
+	case total <= math.MaxUint8:
+		return "uint8"
+	case total <= math.MaxUint16:
+		return "uint16"
+	default:
+		return "uint32"
 	}
+}
 
-	scanner := bufio.NewScanner(file)
-	enumRegex := regexp.MustCompile(`^\s*//\s*ENUM\s+(\w+)\s*\((.*?)\)`)
+func processFile(filename, output string, opts genOptions) error {
+	logger.Info("scanning file", "file", filename)
+	def, err := loadOrderedFile(filename, output, opts)
+	if err != nil {
+		return err
+	}
+	groups, err := renderGroups(def, filename, output)
+	if err != nil {
+		return err
+	}
+	var generated [][]byte
+	for _, g := range groups {
+		generated = append(generated, g.Code, g.TestCode)
+	}
+	outputs := codeFiles(def, filename, output)
+	var fieldsCode []byte
+	if opts.FieldConsts {
+		if output == "" {
+			return errors.New("--field-consts requires an output file")
+		}
+		outputs = append(outputs, fieldConstsOutput(output))
+		if fieldsCode, err = renderFieldConsts(filename, outputs, def); err != nil {
+			return fmt.Errorf("generating field constants: %w", err)
+		}
+		generated = append(generated, fieldsCode)
+	}
+	if opts.ListDeps {
+		return listDeps(os.Stdout, generated...)
+	}
+	dir := ""
+	if output != "" && !opts.Hermetic {
+		dir = filepath.Dir(output)
+	}
+	if err := checkCollisions(def.Package, dir, outputs, generated...); err != nil {
+		return err
+	}
+	logger.Info("generated enums", "file", filename, "enums", len(def.Enums), "mappings", len(def.Mappings))
 
-	// Write package declaration and imports
-	imports := []string{
-		"database/sql/driver",
-		"encoding/json",
-		"fmt",
-		"reflect",
-		"strings",
-	}
-	if yaml {
-		imports = append(imports, "gopkg.in/yaml.v3")
+	if opts.VerifyDeterminism {
+		def, err := loadOrderedFile(filename, output, opts)
+		if err != nil {
+			return err
+		}
+		again, err := renderGroups(def, filename, output)
+		if err != nil {
+			return err
+		}
+		for i, g := range groups {
+			if len(again) != len(groups) || !bytes.Equal(g.Code, again[i].Code) || !bytes.Equal(g.TestCode, again[i].TestCode) {
+				return fmt.Errorf("nondeterministic output generated for %s", filename)
+			}
+		}
 	}
 
-	fmt.Fprintf(out, "package %s\n\n", pkgName)
-	fmt.Fprintln(out, "import (")
-	for _, imp := range imports {
-		fmt.Fprintf(out, "\t%q\n", imp)
-	}
-	fmt.Fprintln(out, ")")
-	fmt.Fprintln(out)
-
-	foundEnum := false
-	for scanner.Scan() {
-		line := scanner.Text()
-		if matches := enumRegex.FindStringSubmatch(line); matches != nil {
-			values := make([]valueInfo, 0)
-			for _, v := range strings.Split(matches[2], ",") {
-				v = strings.TrimSpace(v)
-				if v != "" {
-					values = append(values, valueInfo{
-						Original: v,
-						GoName:   sanitizeGoName(v),
-					})
+	if opts.Check {
+		for _, g := range groups {
+			if err := checkOutput(g.Path, g.Code); err != nil {
+				return err
+			}
+			if g.TestCode != nil {
+				if err := checkOutput(testOnlyOutput(g.Path), g.TestCode); err != nil {
+					return err
 				}
 			}
-
-			enum := enumDef{
-				Package: pkgName,
-				Name:    matches[1],
-				Values:  values,
-				YAML:    yaml,
+		}
+		if fieldsCode != nil {
+			if err := checkOutput(fieldConstsOutput(output), fieldsCode); err != nil {
+				return err
 			}
-			if err := generateEnum(out, enum); err != nil {
-				return fmt.Errorf("generating enum %s: %w", enum.Name, err)
+		}
+		if !opts.FieldDocs {
+			return nil
+		}
+		return updateFieldDocs(filename, output, def, true)
+	}
+	if opts.MigrationDir != "" {
+		if err := writeMigration(opts.MigrationDir, opts.MigrationFmt, output, codeFiles(def, filename, output), def); err != nil {
+			return fmt.Errorf("writing migration: %w", err)
+		}
+	}
+	for _, g := range groups {
+		if err := writeOutput(g.Path, g.Code); err != nil {
+			return err
+		}
+		if g.TestCode != nil {
+			if err := writeOutput(testOnlyOutput(g.Path), g.TestCode); err != nil {
+				return err
 			}
-			foundEnum = true
 		}
 	}
-
-	if !foundEnum {
-		return fmt.Errorf("no enum definitions found in %s", filename)
+	if fieldsCode != nil {
+		if err := writeOutput(fieldConstsOutput(output), fieldsCode); err != nil {
+			return err
+		}
+	}
+	required, err := requiredModules(generated...)
+	if err != nil {
+		return err
+	}
+	if opts.DepsOut != "" {
+		if err := writeRequirements(opts.DepsOut, required); err != nil {
+			return fmt.Errorf("writing module requirements: %w", err)
+		}
+	}
+	if !opts.Hermetic {
+		dir := filepath.Dir(filename)
+		if output != "" {
+			dir = filepath.Dir(output)
+		}
+		warnMissingModules(dir, required)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("scanning file: %w", err)
+	if opts.SharedHelpers {
+		if err := writeHelpers(filepath.Dir(output), def.Package); err != nil {
+			return fmt.Errorf("writing shared helpers: %w", err)
+		}
+	}
+	if opts.GenGolden {
+		if err := writeGolden(output, def.Package, def.Enums); err != nil {
+			return fmt.Errorf("writing golden test: %w", err)
+		}
+	}
+	if opts.GenRoundtrip {
+		if err := writeRoundTrip(output, def.Package, def.Enums); err != nil {
+			return fmt.Errorf("writing round-trip test: %w", err)
+		}
+	}
+	if opts.AvroDir != "" {
+		if err := writeAvroSchemas(opts.AvroDir, def.Enums); err != nil {
+			return fmt.Errorf("writing Avro schemas: %w", err)
+		}
+	}
+	if opts.ThriftOut != "" {
+		if err := writeIDL(opts.ThriftOut, thriftTemplate, def); err != nil {
+			return fmt.Errorf("writing Thrift file: %w", err)
+		}
+	}
+	if opts.FBSOut != "" {
+		if err := writeIDL(opts.FBSOut, fbsTemplate, def); err != nil {
+			return fmt.Errorf("writing FlatBuffers schema: %w", err)
+		}
+	}
+	if opts.GraphQLOut != "" {
+		if err := writeIDL(opts.GraphQLOut, graphqlTemplate, def); err != nil {
+			return fmt.Errorf("writing GraphQL schema: %w", err)
+		}
+		dir := filepath.Dir(filename)
+		if output != "" {
+			dir = filepath.Dir(output)
+		}
+		if opts.Hermetic {
+			logger.Info("skipping the gqlgen models, which need the go.mod file", "file", filename)
+		} else if err := writeGQLGenModels(gqlgenModelsPath(opts.GraphQLOut), dir, def); err != nil {
+			return fmt.Errorf("writing gqlgen models: %w", err)
+		}
+	}
+	if opts.FormsOut != "" {
+		if err := writeFormOptions(opts.FormsOut, def); err != nil {
+			return fmt.Errorf("writing form options: %w", err)
+		}
+	}
+	if opts.Template != "" {
+		if err := writeCustomTemplate(opts.Template, opts.TemplateOut, def); err != nil {
+			return fmt.Errorf("rendering %s: %w", opts.Template, err)
+		}
+	}
+	for _, plugin := range opts.Plugins {
+		dir := filepath.Dir(filename)
+		if output != "" {
+			dir = filepath.Dir(output)
+		}
+		if err := runPlugin(plugin, filename, dir, def); err != nil {
+			return err
+		}
+	}
+	if opts.Changelog != "" {
+		if err := writeChangelog(opts.Changelog, filename, opts.ChangelogRef, def, opts); err != nil {
+			return fmt.Errorf("writing changelog: %w", err)
+		}
+	}
+	if len(def.Mappings) > 0 && output != "" {
+		if err := writeMappingTest(output, def); err != nil {
+			return fmt.Errorf("writing mapping test: %w", err)
+		}
+	}
+	if opts.FieldDocs {
+		if err := updateFieldDocs(filename, output, def, false); err != nil {
+			return fmt.Errorf("updating field docs: %w", err)
+		}
 	}
 	return nil
 }
 
-func generateEnum(w io.Writer, enum enumDef) error {
-	funcMap := template.FuncMap{
-		"title": strings.Title,
-		"lower": strings.ToLower,
-		"goName": func(v valueInfo) string {
-			return v.GoName
-		},
-		"original": func(v valueInfo) string {
-			return v.Original
-		},
+// loadFile returns the enums and mappings declared in filename.
+func loadFile(filename string, opts genOptions) (fileDef, error) {
+	if opts.PackageName != "" {
+		return parseFile(filename, opts.PackageName, opts)
+	}
+	pkgName, err := getPackageName(filename)
+	if err != nil {
+		return fileDef{}, fmt.Errorf("getting package name: %w", err)
 	}
 
-	const enumTemplate = `
-// {{ .Name }} is an enum.
-// Possible values: {{ range $i, $v := .Values }}{{if $i}}, {{end}}{{ original $v }}{{end}}
-// see https://threedots.tech/post/safer-enums-in-go/
-type {{ .Name }} struct {
-	slug string
+	return parseFile(filename, pkgName, opts)
 }
 
-// String returns the string representation of a {{ .Name }} enum.
-func (e {{ .Name }}) String() string {
-	return e.slug
+// loadOrderedFile loads filename with the translations of its enums, keeping
+// the order of the members previously generated into output in append-only mode.
+func loadOrderedFile(filename, output string, opts genOptions) (fileDef, error) {
+	def, err := loadFile(filename, opts)
+	if err != nil {
+		return def, err
+	}
+	if opts.Translations != "" {
+		if def, err = applyTranslations(def, opts.Translations); err != nil {
+			return def, fmt.Errorf("reading translations: %w", err)
+		}
+	}
+	if !opts.AppendOnly || output == "" {
+		return def, nil
+	}
+	for _, file := range codeFiles(def, filename, output) {
+		if def, err = keepPreviousOrder(def, file); err != nil {
+			return def, err
+		}
+	}
+	return def, nil
 }
 
-// Parse sets the enum value from a string.
-func (e *{{ .Name }}) Parse(s string) error {
-	s = strings.TrimSpace(s)
-	switch {
-	{{- range .Values }}
-	case strings.EqualFold(s, {{ $.Name }}{{ goName . | title }}.slug):
-		e.slug = {{ $.Name }}{{ goName . | title }}.slug
-		return nil
-	{{- end }}
+// renderOutputs returns the code of the enums of def generated into output,
+// and the one of the test-only enums generated into its test file (nil if
+// there are none), both starting with the generated code header.
+func renderOutputs(def fileDef, output string) (code, testCode []byte, err error) {
+	header := generatedHeader("go-safe-enum-generator") + "\n\n"
+	if !hasTestOnly(def) {
+		if code, err = renderFile(def); err != nil {
+			return nil, nil, err
+		}
+		return append([]byte(header), code...), nil, nil
 	}
-
-	*e = {{ $.Name }}{{ goName (index .Values 0) | title }}
-	return fmt.Errorf("unknown {{ .Name | lower }}: %s", s)
+	if output == "" {
+		return nil, nil, errors.New("test-only enums require an output file")
+	}
+	prod, test, err := splitTestOnly(def)
+	if err != nil {
+		return nil, nil, err
+	}
+	if code, err = renderFile(prod); err != nil {
+		return nil, nil, err
+	}
+	if testCode, err = renderFile(test); err != nil {
+		return nil, nil, err
+	}
+	return append([]byte(header), code...), append([]byte(header), testCode...), nil
 }
 
-// {{ .Name }}FromString returns a {{ .Name }} from a string.
-func {{ .Name }}FromString(s string) ({{ .Name }}, error) {
-	e := {{ .Name }}{}
-	err := e.Parse(s)
-	return e, err
-}
+// renderFile generates the code for the enums and mappings of def.
+// The result only depends on the input and the options: values keep their
+// declaration order and line endings are normalized to "\n".
+func renderFile(def fileDef) ([]byte, error) {
+	var out bytes.Buffer
 
-// {{ .Name }}FromInt returns a {{ .Name }} from a numeric value.
-func {{ .Name }}FromInt(value int) ({{ .Name }}, error) {
-	if v, ok := {{ .Name | lower }}IntMap[value]; ok {
-		return v, nil
+	// Write package declaration and imports
+	imports := enumImports(def)
+
+	fmt.Fprintf(&out, "package %s\n\n", def.Package)
+	fmt.Fprintln(&out, "import (")
+	for _, imp := range imports {
+		fmt.Fprintf(&out, "\t%q\n", imp)
 	}
-	return {{ .Name }}{}, fmt.Errorf("can't convert the value %d to a {{ .Name }}", value)
-}
+	fmt.Fprintln(&out, ")")
+	fmt.Fprintln(&out)
 
-// {{ .Name }}SchemaConverter is for gorilla/schema (must be registered with decoder.RegisterConverter).
-func {{ .Name }}SchemaConverter(value string) reflect.Value {
-	var e {{ .Name }}
-	if err := e.Parse(value); err != nil {
-		return reflect.ValueOf(nil)
+	var parts []func(io.Writer) error
+	for _, enum := range def.Enums {
+		parts = append(parts, func(w io.Writer) error {
+			if err := generateEnum(w, enum); err != nil {
+				return fmt.Errorf("generating enum %s: %w", enum.Name, err)
+			}
+			return nil
+		})
+	}
+	for _, m := range def.Mappings {
+		parts = append(parts, func(w io.Writer) error {
+			if err := mappingTemplate.Execute(w, m); err != nil {
+				return fmt.Errorf("generating mapping %s-%s: %w", m.From.Name, m.To.Name, err)
+			}
+			return nil
+		})
+	}
+	for _, p := range def.Protos {
+		parts = append(parts, func(w io.Writer) error {
+			if err := protoTemplate.Execute(w, p); err != nil {
+				return fmt.Errorf("generating %s conversions: %w", p.Enum.Name, err)
+			}
+			return nil
+		})
+	}
+	rendered, err := renderConcurrently(parts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rendered {
+		out.Write(rendered[i].Bytes())
 	}
-	return reflect.ValueOf(e)
-}
 
-// Value implements the driver.Valuer interface for database serialization.
-func (e {{ .Name }}) Value() (driver.Value, error) {
-	return e.slug, nil
+	code := out.Bytes()
+	for _, enum := range def.Enums {
+		if len(enum.Skip) > 0 || len(enum.Rename) > 0 {
+			var err error
+			if code, err = editSymbols(code, def); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return normalizeNewlines(code), nil
 }
 
-// Scan implements the sql.Scanner interface for database deserialization.
-func (e *{{ .Name }}) Scan(value interface{}) error {
-	if value == nil {
-		e.slug = {{ $.Name }}{{ goName (index .Values 0) | title }}.slug
-		return nil
+// renderConcurrently runs the render functions on up to GOMAXPROCS goroutines,
+// each into its own buffer, so that large files are generated faster. It
+// returns the buffers in the order of the functions, or the error of the first
+// failing one, so that the output doesn't depend on the scheduling.
+func renderConcurrently(parts []func(io.Writer) error) ([]bytes.Buffer, error) {
+	bufs := make([]bytes.Buffer, len(parts))
+	errs := make([]error, len(parts))
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, render := range parts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = render(&bufs[i])
+		}()
 	}
-
-	switch v := value.(type) {
-	default:
-		return fmt.Errorf("can't convert to {{ .Name }}, unexpected type %T", v)
-	case int:
-		if found, ok := {{ $.Name | lower }}IntMap[v]; ok {
-			e.slug = found.slug
-		} else {
-			return fmt.Errorf("invalid value %d for {{ .Name }}", v)
-		}
-	case float64:
-		if found, ok := {{ $.Name | lower }}IntMap[int(v)]; ok {
-			e.slug = found.slug
-		} else {
-			return fmt.Errorf("invalid value %f for {{ .Name }}", v)
-		}
-	case []byte:
-		if err := e.Parse(string(v)); err != nil {
-			return fmt.Errorf("can't parse {{ .Name }}: %w", err)
-		}
-		return nil
-	case *string:
-		if err := e.Parse(*v); err != nil {
-			return fmt.Errorf("can't parse {{ .Name }}: %w", err)
-		}
-		return nil
-	case string:
-		if err := e.Parse(v); err != nil {
-			return fmt.Errorf("can't parse {{ .Name }}: %w", err)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
-		return nil
 	}
-	return fmt.Errorf("can't convert to {{ .Name }}, unexpected type %T", value)
-}
-{{ if .YAML }}
-// MarshalYAML implements the yaml.Marshaler interface.
-func (e {{ .Name }}) MarshalYAML() (interface{}, error) {
-	return e.slug, nil
+	return bufs, nil
 }
 
-// UnmarshalYAML implements the yaml.Unmarshaler interface
-func (e *{{ .Name }}) UnmarshalYAML(value *yaml.Node) error {
-	if value == nil {
-		return fmt.Errorf("can't unmarshal nil YAML into {{ .Name }}")
+// enumImports returns the imports needed by the code generated for def.
+func enumImports(def fileDef) []string {
+	needFmt := len(def.Mappings) > 0 || len(def.Protos) > 0
+	var needSQL, needBinary, needJSON, needIter, needReflect, needSlices, needSort, needStrconv, needStrings, needAtomic, needYAML, needEnv, needOTel, needProm, needTF, needHuma, needMongo, needSquirrel, needGoqu, needIO bool
+	for _, enum := range def.Enums {
+		needSQL = needSQL || !enum.Minimal
+		needBinary = needBinary || enum.Binary || enum.Packed
+		needJSON = needJSON || !enum.Minimal
+		needIter = needIter || (!enum.Minimal && enum.GoAtLeast("1.23"))
+		// with shared helpers, fmt is only needed by the YAML methods and the int style String
+		needFmt = needFmt || !enum.SharedHelpers || enum.YAML || enum.Style == "int" || enum.Extends != nil || enum.SubsetOf != nil || enum.Strict || enum.Binary || enum.Hash || enum.List || enum.Set || enum.Fuzzy || enum.Fixed || enum.Packed || enum.GraphQL || enum.Mongo || (!enum.Minimal && (enum.HasUUIDs() || enum.HasCodes() || enum.HasEventTypes() || enum.HasCloudEventTypes() || enum.HasDINames()))
+		needReflect = needReflect || enum.Schema || enum.Env
+		needSlices = needSlices || (enum.Slices && enum.GoAtLeast("1.21"))
+		needSort = needSort || (!enum.Minimal && enum.HasWeights()) || (enum.Slices && !enum.GoAtLeast("1.21"))
+		needIO = needIO || enum.GraphQL
+		needStrconv = needStrconv || enum.Counts || enum.GraphQL
+		needStrings = needStrings || !enum.SharedHelpers || enum.CSV || enum.Strict || enum.List || enum.Fuzzy || len(enum.Labels) > 0 || (!enum.Minimal && enum.HasUUIDs())
+		needAtomic = needAtomic || enum.Counts
+		needYAML = needYAML || enum.YAML
+		needEnv = needEnv || enum.Env
+		needOTel = needOTel || enum.OTel
+		needProm = needProm || enum.Prom
+		needTF = needTF || enum.TF
+		needHuma = needHuma || enum.Huma
+		needMongo = needMongo || enum.Mongo
+		needSquirrel = needSquirrel || enum.SQLBuilder == "squirrel"
+		needGoqu = needGoqu || enum.SQLBuilder == "goqu"
 	}
-	var text string
-	if err := value.Decode(&text); err != nil {
-		return err
+
+	var imports []string
+	if needSQL {
+		imports = append(imports, "database/sql/driver")
 	}
-	if err := e.Parse(text); err != nil {
-		return err
+	if needBinary {
+		imports = append(imports, "encoding/binary")
 	}
-	return nil
-}
-{{ end }}
-// MarshalJSON implements the json.Marshaler interface.
-func (e {{ .Name }}) MarshalJSON() ([]byte, error) {
-	return json.Marshal(e.slug)
-}
-
-// UnmarshalJSON implements the json.Unmarshaler interface.
-func (e *{{ .Name }}) UnmarshalJSON(data []byte) error {
-	if data == nil {
-		return fmt.Errorf("can't unmarshal nil JSON into {{ .Name }}")
+	if needJSON {
+		imports = append(imports, "encoding/json")
 	}
-	var text string
-	if err := json.Unmarshal(data, &text); err != nil {
-		return err
+	if needFmt {
+		imports = append(imports, "fmt")
 	}
-	if err := e.Parse(text); err != nil {
-		return err
+	if needIO {
+		imports = append(imports, "io")
 	}
-	return nil
-}
-
-// MarshalText implements the text marshaller method.
-func (e {{ .Name }}) MarshalText() ([]byte, error) {
-	return []byte(e.slug), nil
-}
-
-// UnmarshalText implements the text unmarshaller method.
-func (e *{{ .Name }}) UnmarshalText(data []byte) error {
-	if data == nil {
-		return fmt.Errorf("can't unmarshal empty text into {{ .Name }}")
+	if needIter {
+		imports = append(imports, "iter")
 	}
-	if err := e.Parse(string(data)); err != nil {
-		return err
+	if needReflect {
+		imports = append(imports, "reflect")
 	}
-	return nil
+	if needSlices {
+		imports = append(imports, "slices")
+	}
+	if needSort {
+		imports = append(imports, "sort")
+	}
+	if needStrconv {
+		imports = append(imports, "strconv")
+	}
+	if needStrings {
+		imports = append(imports, "strings")
+	}
+	if needAtomic {
+		imports = append(imports, "sync/atomic")
+	}
+	if needYAML {
+		imports = append(imports, "gopkg.in/yaml.v3")
+	}
+	if needSquirrel {
+		imports = append(imports, "github.com/Masterminds/squirrel")
+	}
+	if needEnv {
+		imports = append(imports, "github.com/caarlos0/env/v11")
+	}
+	if needHuma {
+		imports = append(imports, "github.com/danielgtaylor/huma/v2")
+	}
+	if needGoqu {
+		imports = append(imports, "github.com/doug-martin/goqu/v9", "github.com/doug-martin/goqu/v9/exp")
+	}
+	if needProm {
+		imports = append(imports, "github.com/prometheus/client_golang/prometheus")
+	}
+	if needTF {
+		imports = append(imports,
+			"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator",
+			"github.com/hashicorp/terraform-plugin-framework/schema/validator",
+		)
+	}
+	if needMongo {
+		imports = append(imports, "go.mongodb.org/mongo-driver/v2/bson")
+	}
+	if needOTel {
+		imports = append(imports, "go.opentelemetry.io/otel/attribute")
+	}
+	return imports
 }
 
-// Values returns the list of possible values for the enum.
-func (e *{{ .Name }}) Values() []{{ .Name }} {
-	return append([]{{ .Name }}{}, {{ .Name | lower }}Values...)
+// normalizeNewlines converts CRLF and CR line endings to LF, so that the
+// output doesn't depend on the platform the templates were checked out on.
+func normalizeNewlines(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(b, []byte("\r"), []byte("\n"))
 }
 
-var (
-	{{ .Name | lower }}Values   = []{{ .Name }}{{"{"}}{{ range $i, $v := .Values }}{{if $i}}, {{end}}{{ $.Name }}{{ goName $v | title }}{{end}}{{"}"}}
-	{{- range $i, $v := .Values }}
-	{{ $.Name }}{{ goName $v | title }} = {{ $.Name }}{"{{ original $v }}"}
-	{{- end }}
-	{{ .Name | lower }}IntMap   = map[int]{{ .Name }}{
-		{{- range $i, $v := .Values }}
-		{{ $i }}: {{ $.Name }}{{ goName $v | title }},
-		{{- end }}
+// fileNewline returns the line ending of a hand-written file, CRLF or LF, to
+// keep when rewriting some of its lines.
+func fileNewline(b []byte) string {
+	if bytes.Contains(b, []byte("\r\n")) {
+		return "\r\n"
 	}
-)
-`
+	return "\n"
+}
 
-	tmpl, err := template.New("enum").Funcs(funcMap).Parse(enumTemplate)
-	if err != nil {
-		return fmt.Errorf("parsing template: %w", err)
+// writeOutput writes data to the named file, or to stdout if output is empty.
+func writeOutput(output string, data []byte) error {
+	logger.Debug("writing output", "path", output, "bytes", len(data))
+	if output == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return &writeError{fmt.Errorf("writing output: %w", err)}
+		}
+		return nil
 	}
+	if err := os.WriteFile(output, data, 0o644); err != nil {
+		return &writeError{fmt.Errorf("creating output file: %w", err)}
+	}
+	return nil
+}
 
-	if err := tmpl.Execute(w, enum); err != nil {
+func generateEnum(w io.Writer, enum enumDef) error {
+	if err := enumTemplate.Execute(w, enum); err != nil {
 		return fmt.Errorf("executing template: %w", err)
 	}
 
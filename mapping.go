@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// enumMapping is a one-to-one correspondence between the values of two enums.
+type enumMapping struct {
+	From  enumDef
+	To    enumDef
+	Pairs []mappingPair
+}
+
+type mappingPair struct {
+	From valueInfo
+	To   valueInfo
+}
+
+// newMapping builds the mapping between the enums named from and to, which
+// must be among the already parsed enums, given "from=to" value pairs.
+// Every value may appear at most once on each side; the generated test checks
+// that all the members of both enums are covered.
+func newMapping(enums []enumDef, from, to string, pairs []valueInfo) (enumMapping, error) {
+	fromEnum, toEnum := findEnum(enums, from), findEnum(enums, to)
+	if fromEnum == nil {
+		return enumMapping{}, fmt.Errorf("unknown enum %s (it must be declared before)", from)
+	}
+	if toEnum == nil {
+		return enumMapping{}, fmt.Errorf("unknown enum %s (it must be declared before)", to)
+	}
+
+	m := enumMapping{From: *fromEnum, To: *toEnum}
+	seenFrom, seenTo := map[string]bool{}, map[string]bool{}
+	for _, p := range pairs {
+		fromValue, toValue, ok := strings.Cut(p.Original, "=")
+		if !ok {
+			return enumMapping{}, fmt.Errorf("invalid pair %q (must be %s_value=%s_value)", p.Original, from, to)
+		}
+		fromValue, toValue = strings.TrimSpace(fromValue), strings.TrimSpace(toValue)
+
+		fv, ok := findValue(fromEnum.Values, fromValue)
+		if !ok {
+			return enumMapping{}, fmt.Errorf("value %q is not declared by %s", fromValue, from)
+		}
+		tv, ok := findValue(toEnum.Values, toValue)
+		if !ok {
+			return enumMapping{}, fmt.Errorf("value %q is not declared by %s", toValue, to)
+		}
+		if seenFrom[fromValue] {
+			return enumMapping{}, fmt.Errorf("value %q of %s is mapped more than once", fromValue, from)
+		}
+		if seenTo[toValue] {
+			return enumMapping{}, fmt.Errorf("value %q of %s is mapped more than once", toValue, to)
+		}
+		seenFrom[fromValue], seenTo[toValue] = true, true
+
+		m.Pairs = append(m.Pairs, mappingPair{From: fv, To: tv})
+	}
+	return m, nil
+}
+
+// findValue returns the value with the given original string.
+func findValue(values []valueInfo, original string) (valueInfo, bool) {
+	for _, v := range values {
+		if v.Original == original {
+			return v, true
+		}
+	}
+	return valueInfo{}, false
+}
+
+// writeMappingTest writes a test checking that the mappings of def cover all
+// the members of the mapped enums, next to output.
+func writeMappingTest(output string, def fileDef) error {
+	var buf bytes.Buffer
+	if err := mappingTestTemplate.Execute(&buf, def); err != nil {
+		return fmt.Errorf("executing mapping test template: %w", err)
+	}
+	testFile := strings.TrimSuffix(output, ".go") + "_mapping_test.go"
+	return writeOutput(testFile, normalizeNewlines(buf.Bytes()))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// keepPreviousOrder reorders the values of the enums of def as in the code
+// previously generated into output, so that the members keep their ints: the
+// values missing from it are appended in declaration order. Since the ints of
+// the following members would change, removing values is an error.
+func keepPreviousOrder(def fileDef, output string) (fileDef, error) {
+	previous, err := generatedOrder(output)
+	if errors.Is(err, os.ErrNotExist) {
+		return def, nil
+	}
+	if err != nil {
+		return def, fmt.Errorf("reading previous output: %w", err)
+	}
+
+	for i := range def.Enums {
+		enum := &def.Enums[i]
+		members, ok := previous[enum.VarPrefix()+"Values"]
+		if !ok {
+			continue
+		}
+
+		byMember := map[string]valueInfo{}
+		for _, v := range enum.Values {
+			byMember[enum.Name+strings.Title(v.GoName)] = v
+		}
+		values := make([]valueInfo, 0, len(enum.Values))
+		for _, m := range members {
+			v, ok := byMember[m]
+			if !ok {
+				return def, fmt.Errorf("%s: member %s was removed, which append-only mode doesn't allow", enum.Name, m)
+			}
+			values = append(values, v)
+			delete(byMember, m)
+		}
+		for _, v := range enum.Values {
+			if _, added := byMember[enum.Name+strings.Title(v.GoName)]; added {
+				values = append(values, v)
+			}
+		}
+		enum.Values = values
+	}
+	return def, nil
+}
+
+// generatedOrder returns the members listed by the <enum>Values variables of
+// a generated file, by variable name.
+func generatedOrder(filename string) (map[string][]string, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	order := map[string][]string{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for i, name := range spec.Names {
+			if !strings.HasSuffix(name.Name, "Values") || i >= len(spec.Values) {
+				continue
+			}
+			lit, ok := spec.Values[i].(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			var members []string
+			for _, elt := range lit.Elts {
+				if id, ok := elt.(*ast.Ident); ok {
+					members = append(members, id.Name)
+				}
+			}
+			order[name.Name] = members
+		}
+		return false
+	})
+	return order, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// writeMigration writes to dir a goose or Atlas migration creating the
+// PostgreSQL types of the enums that weren't in the previously generated
+// output (and the other code files of the enums), and adding the values it
+// didn't have. Nothing is written when the types are up to date. It must run
+// before the files are overwritten.
+func writeMigration(dir, format, output string, files []string, def fileDef) error {
+	if output == "" {
+		return errors.New("migrations require an output file")
+	}
+	previous := map[string][]string{}
+	for _, file := range files {
+		order, err := generatedOrder(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("reading previous output: %w", err)
+		}
+		for name, members := range order {
+			previous[name] = members
+		}
+	}
+
+	var up, down []string
+	addsValues := false
+	for _, enum := range def.Enums {
+		typ := strings.ToLower(screamingSnake(enum.Name))
+		members, ok := previous[enum.VarPrefix()+"Values"]
+		if !ok {
+			values := make([]string, len(enum.Values))
+			for i, v := range enum.Values {
+				values[i] = sqlQuote(v.Original)
+			}
+			up = append(up, fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", typ, strings.Join(values, ", ")))
+			down = append([]string{fmt.Sprintf("DROP TYPE %s;", typ)}, down...)
+			continue
+		}
+
+		known := map[string]bool{}
+		for _, m := range members {
+			known[m] = true
+		}
+		isKnown := func(v valueInfo) bool {
+			return known[enum.Name+strings.Title(v.GoName)]
+		}
+		// the values added before all the existing ones are inserted before
+		// the first of them
+		anchor := ""
+		for _, v := range enum.Values {
+			if isKnown(v) {
+				anchor = v.Original
+				break
+			}
+		}
+		for i, v := range enum.Values {
+			if isKnown(v) {
+				continue
+			}
+			// keep the declaration order, which is the sort order of the type;
+			// the previous value either existed or was added by the previous
+			// statement
+			stmt := fmt.Sprintf("ALTER TYPE %s ADD VALUE %s", typ, sqlQuote(v.Original))
+			switch {
+			case i > 0:
+				stmt += " AFTER " + sqlQuote(enum.Values[i-1].Original)
+			case anchor != "":
+				stmt += " BEFORE " + sqlQuote(anchor)
+			}
+			up = append(up, stmt+";")
+			addsValues = true
+			down = append([]string{fmt.Sprintf("-- PostgreSQL can't remove %s from %s", sqlQuote(v.Original), typ)}, down...)
+		}
+	}
+	if len(up) == 0 {
+		return nil
+	}
+
+	// ALTER TYPE ... ADD VALUE can't run in a transaction before PostgreSQL
+	// 12, and the values it adds can't be used in the same transaction after
+	var content string
+	switch format {
+	case "atlas":
+		// Atlas migrations have no down section
+		if addsValues {
+			content = "-- atlas:txmode none\n\n"
+		}
+		content += strings.Join(up, "\n") + "\n"
+	default:
+		if addsValues {
+			content = "-- +goose NO TRANSACTION\n"
+		}
+		content += "-- +goose Up\n" + strings.Join(up, "\n") + "\n\n-- +goose Down\n" + strings.Join(down, "\n") + "\n"
+	}
+	name := fmt.Sprintf("%s_%s_enums.sql", time.Now().UTC().Format("20060102150405"), def.Package)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating migrations directory: %w", err)
+	}
+	return writeOutput(filepath.Join(dir, name), []byte(content))
+}
+
+// sqlQuote returns s as a SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrationAddsValuesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	input, output := filepath.Join(dir, "types.go"), filepath.Join(dir, "types_gen.go")
+	migrations := filepath.Join(dir, "migrations")
+	opts := defaultGenOptions()
+	opts.MigrationDir, opts.MigrationFmt = migrations, "goose"
+
+	generate := func(values string) string {
+		t.Helper()
+		if err := os.WriteFile(input, []byte("package p\n\n// ENUM Color ("+values+")\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(migrations); err != nil {
+			t.Fatal(err)
+		}
+		if err := processFile(input, output, opts); err != nil {
+			t.Fatal(err)
+		}
+		files, err := filepath.Glob(filepath.Join(migrations, "*.sql"))
+		if err != nil || len(files) != 1 {
+			t.Fatalf("migrations: %v, %v", files, err)
+		}
+		data, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if got := generate("red, green"); strings.Contains(got, "NO TRANSACTION") || !strings.Contains(got, "CREATE TYPE color AS ENUM ('red', 'green');") {
+		t.Errorf("creating migration:\n%s", got)
+	}
+	got := generate("black, white, red, blue, green")
+	want := `-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE color ADD VALUE 'black' BEFORE 'red';
+ALTER TYPE color ADD VALUE 'white' AFTER 'black';
+ALTER TYPE color ADD VALUE 'blue' AFTER 'red';
+`
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("altering migration:\n%s\nwant it starting with:\n%s", got, want)
+	}
+}
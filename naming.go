@@ -0,0 +1,146 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms are the words written in upper case by camelCase and
+// pascalCase, as in Go identifiers.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "QPS": true, "RAM": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// splitWords splits s into words at the characters other than letters and
+// digits, and at case changes, keeping initialisms whole (HTTPServer is HTTP
+// and Server).
+func splitWords(s string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			endsInitialism := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || endsInitialism {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// snakeCase returns s as snake_case.
+func snakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+// kebabCase returns s as kebab-case.
+func kebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// pascalCase returns s as PascalCase, with the common initialisms in upper
+// case (http-url-id is HTTPURLID).
+func pascalCase(s string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(s) {
+		sb.WriteString(titleWord(word))
+	}
+	return sb.String()
+}
+
+// camelCase returns s as camelCase, with the common initialisms other than
+// the first word in upper case (user-id is userID).
+func camelCase(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(words[0]))
+	for _, word := range words[1:] {
+		sb.WriteString(titleWord(word))
+	}
+	return sb.String()
+}
+
+// titleWord returns word in title case, or in upper case if it's a common
+// initialism.
+func titleWord(word string) string {
+	if upper := strings.ToUpper(word); commonInitialisms[upper] {
+		return upper
+	}
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// irregularPlurals are the plurals of the English words not following the
+// rules of pluralize.
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"foot":   "feet",
+	"man":    "men",
+	"mouse":  "mice",
+	"person": "people",
+	"woman":  "women",
+}
+
+// pluralize returns the English plural of the last word of s, keeping its
+// case: Category is Categories, HTTPStatus is HTTPStatuses and UserID is
+// UserIDs.
+func pluralize(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return s
+	}
+	last := words[len(words)-1]
+	if !strings.HasSuffix(s, last) {
+		return s
+	}
+	stem := s[:len(s)-len(last)]
+	if commonInitialisms[last] {
+		return s + "s"
+	}
+	lower := strings.ToLower(last)
+	upper := last == strings.ToUpper(last) && len(last) > 1
+
+	plural, ok := irregularPlurals[lower]
+	switch {
+	case ok:
+	case strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "x") || strings.HasSuffix(lower, "z") ||
+		strings.HasSuffix(lower, "ch") || strings.HasSuffix(lower, "sh"):
+		plural = lower + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		plural = lower[:len(lower)-1] + "ies"
+	default:
+		plural = lower + "s"
+	}
+	if upper {
+		return stem + strings.ToUpper(plural)
+	}
+	// keep the case of the letters shared with the singular
+	n := 0
+	for n < len(lower) && n < len(plural) && lower[n] == plural[n] {
+		n++
+	}
+	return stem + last[:n] + plural[n:]
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// outputGroup is a file of generated code, with the definitions rendered into
+// it and the resulting code.
+type outputGroup struct {
+	Path string
+	Def  fileDef
+	// Code and TestCode are the code generated into Path and into its test
+	// file (nil if there are no test-only enums).
+	Code, TestCode []byte
+}
+
+// splitOutputs groups the enums of def by the file they are generated into:
+// output, or the file named by their out option, in the directory of output
+// (of filename when writing to stdout). The output group comes first, and is
+// left out if empty while others aren't. Mappings and protobuf conversions go
+// with the enum they convert from.
+func splitOutputs(def fileDef, filename, output string) []outputGroup {
+	dir := filepath.Dir(filename)
+	if output != "" {
+		dir = filepath.Dir(output)
+	}
+	groups := []outputGroup{{Path: output, Def: fileDef{Package: def.Package}}}
+	groupOf := map[string]int{}
+	for _, enum := range def.Enums {
+		i := 0
+		if path := filepath.Join(dir, enum.Out); enum.Out != "" && (output == "" || path != filepath.Clean(output)) {
+			var ok bool
+			if i, ok = groupOf[path]; !ok {
+				i = len(groups)
+				groupOf[path] = i
+				groups = append(groups, outputGroup{Path: path, Def: fileDef{Package: def.Package}})
+			}
+		}
+		groups[i].Def.Enums = append(groups[i].Def.Enums, enum)
+	}
+	group := func(name string) *outputGroup {
+		for i := range groups {
+			if findEnum(groups[i].Def.Enums, name) != nil {
+				return &groups[i]
+			}
+		}
+		return &groups[0]
+	}
+	for _, m := range def.Mappings {
+		g := group(m.From.Name)
+		g.Def.Mappings = append(g.Def.Mappings, m)
+	}
+	for _, p := range def.Protos {
+		g := group(p.Enum.Name)
+		g.Def.Protos = append(g.Def.Protos, p)
+	}
+	if len(groups) > 1 && len(groups[0].Def.Enums) == 0 && len(groups[0].Def.Mappings) == 0 {
+		groups = groups[1:]
+	}
+	return groups
+}
+
+// renderGroups renders the enums of def into the files they are generated
+// into.
+func renderGroups(def fileDef, filename, output string) ([]outputGroup, error) {
+	groups := splitOutputs(def, filename, output)
+	for i := range groups {
+		var err error
+		if groups[i].Code, groups[i].TestCode, err = renderOutputs(groups[i].Def, groups[i].Path); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// codeFiles returns the files the code of the enums of def is generated into:
+// output, the ones named by out options and their test files with test-only
+// enums.
+func codeFiles(def fileDef, filename, output string) []string {
+	var files []string
+	for _, g := range splitOutputs(def, filename, output) {
+		if g.Path == "" {
+			continue
+		}
+		files = append(files, g.Path)
+		if hasTestOnly(g.Def) {
+			files = append(files, testOnlyOutput(g.Path))
+		}
+	}
+	return files
+}
+
+// generatedFiles returns the files processFile may write for the enums of def
+// besides the migrations, which are written once: the code files, and those
+// requested by opts. Some of them are only written when needed (the missing
+// golden files, the gqlgen models of modules).
+func generatedFiles(def fileDef, filename, output string, opts genOptions) []string {
+	files := codeFiles(def, filename, output)
+	add := func(path string) {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	add(opts.DepsOut)
+	if opts.SharedHelpers {
+		add(filepath.Join(filepath.Dir(output), helpersFilename))
+	}
+	if opts.GenGolden {
+		add(strings.TrimSuffix(output, ".go") + "_golden_test.go")
+		for _, enum := range def.Enums {
+			add(filepath.Join(filepath.Dir(output), goldenPath(enum.Name)))
+		}
+	}
+	if opts.GenRoundtrip {
+		add(strings.TrimSuffix(output, ".go") + "_roundtrip_test.go")
+	}
+	if opts.AvroDir != "" {
+		for _, enum := range def.Enums {
+			add(filepath.Join(opts.AvroDir, enum.Name+".avsc"))
+		}
+	}
+	add(opts.ThriftOut)
+	add(opts.FBSOut)
+	if opts.GraphQLOut != "" {
+		add(opts.GraphQLOut)
+		add(gqlgenModelsPath(opts.GraphQLOut))
+	}
+	add(opts.FormsOut)
+	if opts.Template != "" {
+		add(opts.TemplateOut)
+	}
+	if len(def.Mappings) > 0 {
+		add(strings.TrimSuffix(output, ".go") + "_mapping_test.go")
+	}
+	return files
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pluginPrefix prefixes the executables of the plugins named without a path.
+const pluginPrefix = "safe-enum-gen-"
+
+// pluginVersion is the version of the plugin protocol, increased on
+// incompatible changes.
+const pluginVersion = 1
+
+// pluginRequest is written as JSON to the standard input of plugins.
+type pluginRequest struct {
+	Version   int             `json:"version"`
+	File      string          `json:"file"`
+	Package   string          `json:"package"`
+	Parameter string          `json:"parameter,omitempty"`
+	Enums     []pluginEnum    `json:"enums"`
+	Mappings  []pluginMapping `json:"mappings,omitempty"`
+}
+
+type pluginEnum struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Style     string        `json:"style"`
+	Options   []string      `json:"options"`
+	Extends   string        `json:"extends,omitempty"`
+	SubsetOf  string        `json:"subsetOf,omitempty"`
+	Values    []pluginValue `json:"values"`
+}
+
+type pluginValue struct {
+	Value string `json:"value"`
+	// Member is the Go identifier of the value.
+	Member string `json:"member"`
+	Int    int    `json:"int"`
+	Doc    string `json:"doc,omitempty"`
+	// Attrs are the attributes declared in brackets after the value.
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+type pluginMapping struct {
+	From  string            `json:"from"`
+	To    string            `json:"to"`
+	Pairs map[string]string `json:"pairs"`
+}
+
+// pluginResponse is read as JSON from the standard output of plugins.
+type pluginResponse struct {
+	Error string       `json:"error,omitempty"`
+	Files []pluginFile `json:"files"`
+}
+
+type pluginFile struct {
+	// Name is relative to the directory of the output file.
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// newPluginRequest returns the request describing def to plugins.
+func newPluginRequest(filename, parameter string, def fileDef) pluginRequest {
+	req := pluginRequest{Version: pluginVersion, File: filename, Package: def.Package, Parameter: parameter}
+	for _, enum := range def.Enums {
+		e := pluginEnum{
+			Name:      enum.Name,
+			Namespace: enum.Namespace,
+			Style:     enum.Style,
+			Options:   enabledFeatures(enum)[1:],
+		}
+		if enum.Extends != nil {
+			e.Extends = enum.Extends.Name
+		}
+		if enum.SubsetOf != nil {
+			e.SubsetOf = enum.SubsetOf.Name
+		}
+		for i, v := range enum.Values {
+			e.Values = append(e.Values, pluginValue{
+				Value:  v.Original,
+				Member: enum.Name + strings.Title(v.GoName),
+				Int:    i,
+				Doc:    v.Doc,
+				Attrs:  v.Attrs,
+			})
+		}
+		req.Enums = append(req.Enums, e)
+	}
+	for _, m := range def.Mappings {
+		pm := pluginMapping{From: m.From.Name, To: m.To.Name, Pairs: map[string]string{}}
+		for _, p := range m.Pairs {
+			pm.Pairs[p.From.Original] = p.To.Original
+		}
+		req.Mappings = append(req.Mappings, pm)
+	}
+	return req
+}
+
+// runPlugin runs the plugin spec ("name[=parameter]") on def, writing the
+// files it returns to dir. Plugins named without a path are looked up in
+// PATH as safe-enum-gen-<name>.
+func runPlugin(spec, filename, dir string, def fileDef) error {
+	name, parameter, _ := strings.Cut(spec, "=")
+	path := name
+	if !strings.ContainsRune(name, filepath.Separator) && !strings.ContainsRune(name, '/') {
+		var err error
+		if path, err = exec.LookPath(pluginPrefix + name); err != nil {
+			return fmt.Errorf("plugin %s: %w", name, err)
+		}
+	}
+
+	input, err := json.Marshal(newPluginRequest(filename, parameter, def))
+	if err != nil {
+		return err
+	}
+	var stdout bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = bytes.NewReader(input), &stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s: %w", name, err)
+	}
+	var resp pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return fmt.Errorf("plugin %s: invalid response: %w", name, err)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("plugin %s: %s", name, resp.Error)
+	}
+
+	for _, f := range resp.Files {
+		if !filepath.IsLocal(f.Name) {
+			return fmt.Errorf("plugin %s: file name %q isn't a relative path inside the output directory", name, f.Name)
+		}
+		target := filepath.Join(dir, f.Name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := writeOutput(target, []byte(f.Content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// protocPluginPrefix is the prefix protoc looks plugin binaries up by: when
+// the generator is installed as protoc-gen-safe-enum it runs as a plugin.
+const protocPluginPrefix = "protoc-gen-"
+
+// protoEnum is a safe enum wrapping an enum generated by protoc-gen-go.
+type protoEnum struct {
+	Enum enumDef
+	// Proto is the Go type of the wrapped enum.
+	Proto string
+	// Values are the Go constants of the wrapped enum, matching Enum.Values.
+	Values []string
+}
+
+// runProtocPlugin reads a CodeGeneratorRequest from stdin and writes next to
+// each .pb.go file a <name>_safe_enum.pb.go file wrapping its enums.
+// Besides the standard ones (paths, module, M...), the plugin parameters are
+// the directive options, e.g. "style=const,yaml" (buf's opt entries).
+func runProtocPlugin(opts genOptions) {
+	params := protogen.Options{
+		ParamFunc: func(name, value string) error {
+			if value != "" {
+				name += "=" + value
+			}
+			return parseOptions(&opts, name)
+		},
+	}
+	params.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			if err := generateProtoFile(gen, f, opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func generateProtoFile(gen *protogen.Plugin, f *protogen.File, opts genOptions) error {
+	def := fileDef{Package: string(f.GoPackageName)}
+	for _, e := range protoEnums(f) {
+		p := newProtoEnum(def.Package, e, opts)
+		def.Enums = append(def.Enums, p.Enum)
+		def.Protos = append(def.Protos, p)
+	}
+	if len(def.Enums) == 0 {
+		return nil
+	}
+
+	code, err := renderFile(def)
+	if err != nil {
+		return err
+	}
+	g := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_safe_enum.pb.go", f.GoImportPath)
+	g.P(generatedHeader("protoc-gen-safe-enum"))
+	g.P("// source: ", f.Desc.Path())
+	g.P()
+	_, err = g.Write(code)
+	return err
+}
+
+// protoEnums returns the enums declared in f, including the nested ones.
+func protoEnums(f *protogen.File) []*protogen.Enum {
+	enums := append([]*protogen.Enum{}, f.Enums...)
+	var walk func(messages []*protogen.Message)
+	walk = func(messages []*protogen.Message) {
+		for _, m := range messages {
+			enums = append(enums, m.Enums...)
+			walk(m.Messages)
+		}
+	}
+	walk(f.Messages)
+	return enums
+}
+
+// newProtoEnum returns the safe enum wrapping e, named Safe<Go name of e>.
+// The values are the proto names without the SCREAMING_SNAKE_CASE prefix of
+// the enum name, in lower case (COLOR_DARK_RED becomes dark_red); aliases of
+// an already seen number are skipped.
+func newProtoEnum(pkgName string, e *protogen.Enum, opts genOptions) protoEnum {
+	p := protoEnum{Proto: e.GoIdent.GoName}
+	prefix := screamingSnake(string(e.Desc.Name())) + "_"
+	seen := map[int32]bool{}
+	var values []valueInfo
+	for _, v := range e.Values {
+		if seen[int32(v.Desc.Number())] {
+			continue
+		}
+		seen[int32(v.Desc.Number())] = true
+
+		slug := strings.ToLower(strings.TrimPrefix(string(v.Desc.Name()), prefix))
+		values = append(values, valueInfo{
+			Original: slug,
+			GoName:   sanitizeGoName(slug),
+			Doc:      strings.Join(strings.Fields(string(v.Comments.Leading)), " "),
+		})
+		p.Values = append(p.Values, v.GoIdent.GoName)
+	}
+	p.Enum = newEnumDef(pkgName, "", "Safe"+e.GoIdent.GoName, values, opts)
+	p.Enum.ProtoPrefix = prefix
+	return p
+}
+
+// ProtoJSONName returns the name of v in protobuf JSON: the original value in
+// upper case, with the characters invalid in identifiers replaced by
+// underscores, prefixed by the SCREAMING_SNAKE_CASE enum name.
+func (e enumDef) ProtoJSONName(v valueInfo) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, v.Original)
+	return e.ProtoPrefix + name
+}
+
+// screamingSnake converts a CamelCase name to SCREAMING_SNAKE_CASE.
+func screamingSnake(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
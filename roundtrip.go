@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// writeRoundTrip writes next to output a test converting every member of
+// enums to strings (through Parse and, when generated, JSON, text, YAML and
+// database/sql) and back, and checking that invalid strings are rejected.
+func writeRoundTrip(output, pkgName string, enums []enumDef) error {
+	if output == "" {
+		return errors.New("round-trip tests require an output file")
+	}
+
+	data := struct {
+		Package string
+		Enums   []enumDef
+		JSON    bool
+		YAML    bool
+	}{Package: pkgName, Enums: enums}
+	for _, enum := range enums {
+		data.JSON = data.JSON || !enum.Minimal
+		data.YAML = data.YAML || enum.YAML
+	}
+
+	var buf bytes.Buffer
+	if err := roundtripTemplate.Execute(&buf, data); err != nil {
+		return fmt.Errorf("executing round-trip template: %w", err)
+	}
+	testFile := strings.TrimSuffix(output, ".go") + "_roundtrip_test.go"
+	return writeOutput(testFile, normalizeNewlines(buf.Bytes()))
+}
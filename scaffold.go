@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// newCmd adds an enum directive to a Go file.
+type newCmd struct {
+	Name       string   `arg:"" help:"Name of the enum"`
+	Values     []string `arg:"" help:"Values of the enum (comma or space separated)"`
+	File       string   `help:"File to add the directive to (created if missing)" short:"f" required:""`
+	Options    string   `help:"Directive options, e.g. \"style=const yaml\""`
+	GoGenerate bool     `help:"Also add a go:generate line running the generator on the file, unless present"`
+}
+
+// Run appends the directive (and the go:generate line) to the file.
+func (c *newCmd) Run() error {
+	directive, err := c.directive()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(c.File)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		pkgName, err := guessPackageName(filepath.Dir(c.File))
+		if err != nil {
+			return err
+		}
+		content = []byte("package " + pkgName + "\n")
+	case err != nil:
+		return err
+	default:
+		def, err := loadFile(c.File, defaultGenOptions())
+		var d *diagnostic
+		if err != nil && !(errors.As(err, &d) && d.Code == "no-enums") {
+			return err
+		}
+		if findEnum(def.Enums, c.Name) != nil {
+			return fmt.Errorf("enum %s is already declared in %s", c.Name, c.File)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Write(content)
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	if c.GoGenerate && !bytes.Contains(content, []byte("//go:generate go-safe-enum-generator")) {
+		base := filepath.Base(c.File)
+		fmt.Fprintf(&buf, "\n//go:generate go-safe-enum-generator -f %s -o %s_enum_gen.go\n", base, strings.TrimSuffix(base, ".go"))
+	}
+	fmt.Fprintf(&buf, "\n%s\n", directive)
+	return writeOutput(c.File, buf.Bytes())
+}
+
+// directive returns the ENUM directive line, after checking its parts.
+func (c *newCmd) directive() (string, error) {
+	if !token.IsIdentifier(c.Name) {
+		return "", fmt.Errorf("invalid enum name %q", c.Name)
+	}
+	var values []string
+	seen := map[string]bool{}
+	for _, arg := range c.Values {
+		for _, v := range strings.Split(arg, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			if strings.ContainsAny(v, "()#") {
+				return "", fmt.Errorf("invalid value %q (can't contain parentheses or #)", v)
+			}
+			if seen[strings.ToLower(v)] {
+				return "", fmt.Errorf("value %q is listed more than once", v)
+			}
+			seen[strings.ToLower(v)] = true
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return "", errors.New("no values given")
+	}
+	opts := defaultGenOptions()
+	if err := parseOptions(&opts, c.Options); err != nil {
+		return "", err
+	}
+
+	directive := fmt.Sprintf("// ENUM %s (%s)", c.Name, strings.Join(values, ", "))
+	if options := strings.Join(strings.Fields(c.Options), " "); options != "" {
+		directive += " " + options
+	}
+	return directive, nil
+}
+
+// guessPackageName returns the package of the Go files in dir, or the
+// directory name if there are none.
+func guessPackageName(dir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		if name, err := getPackageName(f); err == nil {
+			return name, nil
+		}
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	name := strings.ToLower(sanitizeGoName(filepath.Base(abs)))
+	return strings.TrimLeft(name, "_"), nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+)
+
+// checkStringerNames checks that the Go names of the values of enum can
+// declare the constants of the stringer option: they can't be keywords, the
+// blank identifier, or shadow the predeclared identifiers of the package.
+func checkStringerNames(enum enumDef) error {
+	for _, v := range enum.Values {
+		switch {
+		case token.IsKeyword(v.GoName):
+			return fmt.Errorf("value %q can't be declared as a constant by the stringer option, %s is a Go keyword", v.Original, v.GoName)
+		case v.GoName == "_":
+			return fmt.Errorf("value %q can't be declared as a constant by the stringer option, it has no Go name", v.Original)
+		case types.Universe.Lookup(v.GoName) != nil:
+			return fmt.Errorf("value %q can't be declared as a constant by the stringer option, it would shadow the predeclared %s", v.Original, v.GoName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseSymbolList parses the comma separated identifiers of a skip option.
+func parseSymbolList(value string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("invalid symbol %q", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// parseRenames parses the comma separated old:new pairs of a rename option.
+func parseRenames(value string) (map[string]string, error) {
+	renames := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		from, to, ok := strings.Cut(pair, ":")
+		if !ok || !token.IsIdentifier(from) || !token.IsIdentifier(to) {
+			return nil, fmt.Errorf("invalid rename %q (must be old:new)", pair)
+		}
+		renames[from] = to
+	}
+	return renames, nil
+}
+
+// symbolEdit replaces the bytes from start to end of the generated code.
+type symbolEdit struct {
+	start, end int
+	text       string
+}
+
+// editSymbols removes the generated functions and methods skipped by the
+// enums of def from code, renames the functions they rename, and drops the
+// imports left unused.
+func editSymbols(code []byte, def fileDef) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", code, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code: %w", err)
+	}
+	offset := func(p token.Pos) int { return fset.Position(p).Offset }
+
+	funcs := map[string]*ast.FuncDecl{}
+	methods := map[string]*ast.FuncDecl{}
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if fn.Recv == nil {
+			funcs[fn.Name.Name] = fn
+		} else {
+			methods[receiverName(fn)+"."+fn.Name.Name] = fn
+		}
+	}
+	// resolve returns the function or method of enum called name, which can
+	// omit the enum name prefixing functions.
+	resolve := func(enum enumDef, name string) (*ast.FuncDecl, error) {
+		if fn, ok := methods[enum.Name+"."+name]; ok {
+			return fn, nil
+		}
+		if fn, ok := funcs[name]; ok {
+			return fn, nil
+		}
+		if fn, ok := funcs[enum.Name+name]; ok {
+			return fn, nil
+		}
+		return nil, fmt.Errorf("%s: no generated function or method %s", enum.Name, name)
+	}
+
+	var edits []symbolEdit
+	skipped := map[*ast.FuncDecl]bool{}
+	renamed := map[string]string{}
+	for _, enum := range def.Enums {
+		for _, name := range enum.Skip {
+			fn, err := resolve(enum, name)
+			if err != nil {
+				return nil, err
+			}
+			if skipped[fn] {
+				continue
+			}
+			skipped[fn] = true
+			start, end := offset(fn.Pos()), offset(fn.End())
+			if fn.Doc != nil {
+				start = offset(fn.Doc.Pos())
+			}
+			// drop the blank line following the declaration as well
+			for n := 0; n < 2 && end < len(code) && code[end] == '\n'; n++ {
+				end++
+			}
+			edits = append(edits, symbolEdit{start, end, ""})
+		}
+		for from, to := range enum.Rename {
+			fn, err := resolve(enum, from)
+			if err != nil {
+				return nil, err
+			}
+			if fn.Recv != nil {
+				return nil, fmt.Errorf("%s: method %s can't be renamed, only functions can", enum.Name, from)
+			}
+			if f.Scope.Lookup(to) != nil {
+				return nil, fmt.Errorf("%s: can't rename %s to %s, which is generated too", enum.Name, fn.Name.Name, to)
+			}
+			renamed[fn.Name.Name] = to
+			if fn.Doc != nil {
+				word := regexp.MustCompile(`\b` + fn.Name.Name + `\b`)
+				start, end := offset(fn.Doc.Pos()), offset(fn.Doc.End())
+				edits = append(edits, symbolEdit{start, end, word.ReplaceAllString(string(code[start:end]), to)})
+			}
+		}
+	}
+
+	var refErr error
+	ast.Inspect(f, func(n ast.Node) bool {
+		if refErr != nil || n == nil {
+			return false
+		}
+		if fn, ok := n.(*ast.FuncDecl); ok && skipped[fn] {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			for fn := range skipped {
+				if fn.Recv != nil && n.Sel.Name == fn.Name.Name {
+					refErr = fmt.Errorf("can't skip %s.%s, which other generated code uses", receiverName(fn), fn.Name.Name)
+				}
+			}
+		case *ast.Ident:
+			for fn := range skipped {
+				if fn.Recv == nil && n.Name == fn.Name.Name && n != fn.Name {
+					refErr = fmt.Errorf("can't skip %s, which other generated code uses", fn.Name.Name)
+				}
+			}
+			if to, ok := renamed[n.Name]; ok {
+				edits = append(edits, symbolEdit{offset(n.Pos()), offset(n.End()), to})
+			}
+		}
+		return true
+	})
+	if refErr != nil {
+		return nil, refErr
+	}
+
+	code = applySymbolEdits(code, edits)
+	return removeUnusedImports(code)
+}
+
+// applySymbolEdits applies edits to code, ignoring the ones inside the
+// removed ranges.
+func applySymbolEdits(code []byte, edits []symbolEdit) []byte {
+	var kept []symbolEdit
+	for _, e := range edits {
+		removed := false
+		for _, r := range edits {
+			removed = removed || (r.text == "" && r != e && e.start >= r.start && e.end <= r.end)
+		}
+		if !removed {
+			kept = append(kept, e)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].start > kept[j].start
+	})
+
+	out := append([]byte(nil), code...)
+	for _, e := range kept {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
+
+// removeUnusedImports removes the imports the code doesn't reference anymore.
+func removeUnusedImports(code []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", code, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing edited code: %w", err)
+	}
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	var edits []symbolEdit
+	for _, spec := range f.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if used[name] || name == "_" {
+			continue
+		}
+		start, end := fset.Position(spec.Pos()).Offset, fset.Position(spec.End()).Offset
+		// remove the whole line
+		start = bytes.LastIndexByte(code[:start], '\n') + 1
+		if end < len(code) && code[end] == '\n' {
+			end++
+		}
+		edits = append(edits, symbolEdit{start, end, ""})
+	}
+	return applySymbolEdits(code, edits), nil
+}
+
+// importName returns the name of the package imported by importPath, assuming
+// it's the last element without the major version.
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if strings.HasPrefix(name, "v") && importPath != name {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = path.Base(path.Dir(importPath))
+		}
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+// receiverName returns the name of the type of the receiver of fn.
+func receiverName(fn *ast.FuncDecl) string {
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
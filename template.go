@@ -0,0 +1,73 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templateFuncs = template.FuncMap{
+	"title": strings.Title,
+	"lower": strings.ToLower,
+	"goName": func(v valueInfo) string {
+		return v.GoName
+	},
+	"original": func(v valueInfo) string {
+		return v.Original
+	},
+	"snake":             snakeCase,
+	"kebab":             kebabCase,
+	"camel":             camelCase,
+	"pascal":            pascalCase,
+	"pluralize":         pluralize,
+	"nameTable":         nameTable,
+	"nameIndex":         nameIndex,
+	"nameIndexType":     nameIndexType,
+	"goldenPath":        goldenPath,
+	"quote":             strconv.Quote,
+	"jsonQuote":         jsonQuote,
+	"fbsType":           fbsType,
+	"clickhouseType":    clickhouseType,
+	"esMapping":         esMapping,
+	"graphqlName":       graphqlName,
+	"graphqlString":     graphqlString,
+	"deprecated":        deprecated,
+	"deprecationReason": deprecationReason,
+	"fuzzyKey":          fuzzyKey,
+	"wireSize":          wireSize,
+	"packBits":          packBits,
+	"generatedHeader":   templateHeader,
+}
+
+// The templates are parsed once at startup and reused for every enum.
+var (
+	enumTemplate    = mustParseTemplate("enum.tmpl")
+	helpersTemplate = mustParseTemplate("helpers.tmpl")
+	goldenTemplate  = mustParseTemplate("golden_test.tmpl")
+	mappingTemplate = mustParseTemplate("mapping.tmpl")
+
+	mappingTestTemplate = mustParseTemplate("mapping_test.tmpl")
+	thriftTemplate      = mustParseTemplate("thrift.tmpl")
+	fbsTemplate         = mustParseTemplate("fbs.tmpl")
+	protoTemplate       = mustParseTemplate("proto.tmpl")
+	roundtripTemplate   = mustParseTemplate("roundtrip_test.tmpl")
+	graphqlTemplate     = mustParseTemplate("graphql.tmpl")
+)
+
+// jsonQuote returns the Go string literal of the JSON encoding of s.
+func jsonQuote(s string) (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Quote(string(data)), nil
+}
+
+func mustParseTemplate(name string) *template.Template {
+	return template.Must(template.New(name).Funcs(templateFuncs).ParseFS(templateFS, "templates/"+name))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+)
+
+// templateCmd groups the commands about custom templates.
+type templateCmd struct {
+	Check templateCheckCmd `cmd:"" help:"Check that a custom template renders valid code"`
+}
+
+// templateCheckCmd renders a custom template with sample enums.
+type templateCheckCmd struct {
+	Template string `arg:"" help:"Template to check" type:"existingfile"`
+	Go       bool   `help:"Check that the result is valid Go (use --no-go for other outputs)" default:"true" negatable:""`
+	Print    bool   `help:"Print the rendered template"`
+}
+
+// Run parses and renders the template, and parses the result as Go.
+func (c *templateCheckCmd) Run() error {
+	tmpl, err := parseCustomTemplate(c.Template)
+	if err != nil {
+		return err
+	}
+	// rendering with the real data types catches the references to missing
+	// fields; it's done as text so that all the Go syntax errors are reported
+	code, err := renderCustomTemplate(tmpl, "", sampleFileDef())
+	if err != nil {
+		return err
+	}
+	if c.Go {
+		if _, err := parser.ParseFile(token.NewFileSet(), "rendered", code, parser.AllErrors); err != nil {
+			if c.Print {
+				os.Stdout.Write(code)
+			}
+			var list scanner.ErrorList
+			if errors.As(err, &list) {
+				for _, e := range list {
+					fmt.Fprintln(os.Stderr, e)
+				}
+			}
+			return fmt.Errorf("invalid Go code: %w", err)
+		}
+	}
+	if c.Print {
+		_, err := os.Stdout.Write(code)
+		return err
+	}
+	fmt.Printf("%s: ok\n", c.Template)
+	return nil
+}
+
+// sampleFileDef returns the data of a file declaring a few enums, using the
+// main styles and features, for checking templates:
+//
+//	// ENUM Color (red, dark-green # the default, blue)
+//	// ENUM Level (low, high) style=int yaml
+//	// ENUM ExtendedColor extends Color (+black) style=const
+//	// ENUM-MAP Color Level (red=high, dark-green=low, blue=low)
+func sampleFileDef() fileDef {
+	opts := defaultGenOptions()
+	color := newEnumDef("sample", "", "Color", []valueInfo{
+		{Original: "red", GoName: "red"},
+		{Original: "dark-green", GoName: sanitizeGoName("dark-green"), Doc: "the default"},
+		{Original: "blue", GoName: "blue"},
+	}, opts)
+
+	levelOpts := opts
+	levelOpts.Style, levelOpts.YAML = "int", true
+	level := newEnumDef("sample", "", "Level", []valueInfo{
+		{Original: "low", GoName: "low"},
+		{Original: "high", GoName: "high"},
+	}, levelOpts)
+
+	extOpts := opts
+	extOpts.Style = "const"
+	extended := newEnumDef("sample", "", "ExtendedColor", append(append([]valueInfo{}, color.Values...),
+		valueInfo{Original: "black", GoName: "black"}), extOpts)
+	extended.Extends = &color
+
+	return fileDef{
+		Package: "sample",
+		Enums:   []enumDef{color, level, extended},
+		Mappings: []enumMapping{{
+			From: color,
+			To:   level,
+			Pairs: []mappingPair{
+				{color.Values[0], level.Values[1]},
+				{color.Values[1], level.Values[0]},
+				{color.Values[2], level.Values[0]},
+			},
+		}},
+	}
+}
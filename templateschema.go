@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// templateSchemaCmd prints the data and functions available to custom templates.
+type templateSchemaCmd struct {
+	Format string `help:"Output format (text, json)" enum:"text,json" default:"text"`
+}
+
+// schemaType describes a type of the template data.
+type schemaType struct {
+	Name    string        `json:"name"`
+	Doc     string        `json:"doc"`
+	Fields  []schemaEntry `json:"fields"`
+	Methods []schemaEntry `json:"methods,omitempty"`
+}
+
+// schemaEntry describes a field, a method or a function.
+type schemaEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+// templateSchema is the documentation of the template data.
+type templateSchema struct {
+	Types []schemaType  `json:"types"`
+	Funcs []schemaEntry `json:"funcs"`
+}
+
+// schemaTypeNames are the names under which the types of the template data
+// are documented, the first one being the root.
+var schemaTypeNames = []struct {
+	name string
+	typ  reflect.Type
+	doc  string
+}{
+	{"File", reflect.TypeOf(fileDef{}), "the root of the data: everything declared by the directives of the input file"},
+	{"Enum", reflect.TypeOf(enumDef{}), "an enum, with the options it is generated with"},
+	{"Value", reflect.TypeOf(valueInfo{}), "a value of an enum"},
+	{"Mapping", reflect.TypeOf(enumMapping{}), "an ENUM-MAP directive"},
+	{"MappingPair", reflect.TypeOf(mappingPair{}), "corresponding values of a mapping"},
+	{"ProtoEnum", reflect.TypeOf(protoEnum{}), "an enum wrapping a protobuf enum (protoc plugin only)"},
+	{"LookupEntry", reflect.TypeOf(lookupEntry{}), "a string accepted by Parse"},
+}
+
+// templateDocs documents the fields and methods of the template data, by
+// "Type.Name", and the functions by name.
+var templateDocs = map[string]string{
+	"File.Package":  "package of the generated code",
+	"File.Enums":    "enums in declaration order",
+	"File.Mappings": "mappings in declaration order",
+	"File.Protos":   "protobuf enum wrappers",
+
+	"Enum.Package":       "package of the generated code",
+	"Enum.Namespace":     "namespace directive the enum belongs to, if any",
+	"Enum.Name":          "Go type name",
+	"Enum.Values":        "values, in the order of their int mapping",
+	"Enum.YAML":          "yaml option",
+	"Enum.Env":           "env option",
+	"Enum.Style":         "style option: struct, const or int",
+	"Enum.Slugs":         "slugs option",
+	"Enum.CSV":           "csv option",
+	"Enum.Schema":        "schema option",
+	"Enum.Strict":        "strict option",
+	"Enum.OTel":          "otel option",
+	"Enum.Prom":          "prometheus option",
+	"Enum.Kube":          "kubebuilder option",
+	"Enum.TF":            "terraform option",
+	"Enum.Null":          "null-zero option (always false for the int style)",
+	"Enum.Fire":          "firestore option",
+	"Enum.Span":          "spanner option",
+	"Enum.CH":            "clickhouse option",
+	"Enum.Binary":        "binary option",
+	"Enum.PJSON":         "protojson option",
+	"Enum.Hash":          "hash option",
+	"Enum.List":          "list option",
+	"Enum.Set":           "set option (also true with set-bitmask)",
+	"Enum.SetBitmask":    "set-bitmask option",
+	"Enum.Counts":        "counts option",
+	"Enum.Fuzzy":         "fuzzy option",
+	"Enum.Fixed":         "fixed-width option",
+	"Enum.Packed":        "packed option",
+	"Enum.Slices":        "slices option",
+	"Enum.Swag":          "swag option",
+	"Enum.Huma":          "huma option",
+	"Enum.Select":        "select option",
+	"Enum.Sorted":        "sorted option",
+	"Enum.ES":            "elasticsearch option",
+	"Enum.Mongo":         "mongo option",
+	"Enum.Kafka":         "kafka option",
+	"Enum.FeatureFlag":   "feature-flag option",
+	"Enum.Stringer":      "stringer option",
+	"Enum.GraphQL":       "whether the enums are written as GraphQL SDL (generating the gqlgen marshalers)",
+	"Enum.CSVSeparator":  "csv-separator option",
+	"Enum.OnParseError":  "on-parse-error option: keep, zero or first",
+	"Enum.ZeroString":    "zero-string option: empty, invalid or default",
+	"Enum.SQLBuilder":    "sql-builder option: squirrel, goqu or empty",
+	"Enum.VarNames":      "var-names option: lower or namespaced",
+	"Enum.AvroNamespace": "avro-namespace option",
+	"Enum.MinGo":         "minimum Go version of the target module, if known",
+	"Enum.ProtoPrefix":   "prefix of the protobuf names of the values (e.g. AUTH_TYPE_)",
+	"Enum.SharedHelpers": "whether the shared helpers are generated",
+	"Enum.Minimal":       "whether minimal mode is on",
+	"Enum.Extends":       "base enum this one extends, if any",
+	"Enum.SubsetOf":      "parent enum this one is a subset of, if any",
+	"Enum.Labels":        "display names of the values by language and value, if translated",
+	"Enum.Skip":          "generated functions and methods removed by the skip option",
+	"Enum.Rename":        "new names of the generated functions, by old name (rename option)",
+	"Enum.TestOnly":      "test-only option",
+	"Enum.Out":           "out option: name of the file the enum is generated into, if not the output",
+
+	"Enum.GoAtLeast":          "reports whether the generated code can use the features of a Go version (e.g. \"1.23\")",
+	"Enum.HasDocs":            "reports whether at least one value is documented",
+	"Enum.SortedValues":       "values in the order the members are declared in (alphabetical with the sorted option)",
+	"Enum.IntOf":              "int of a value",
+	"Enum.HasUUIDs":           "reports whether the values declare uuid attributes",
+	"Enum.HasCodes":           "reports whether the values declare code attributes",
+	"Enum.HasWeights":         "reports whether the values declare weight attributes",
+	"Enum.HasTopics":          "reports whether the values declare topic attributes",
+	"Enum.Topics":             "distinct topic attributes of the values, in order of declaration",
+	"Enum.HasEventTypes":      "reports whether the values declare event attributes",
+	"Enum.HasCloudEventTypes": "reports whether the values declare ce-type attributes",
+	"Enum.HasDINames":         "reports whether the values declare di attributes",
+	"Enum.WeightOf":           "weight declared for a value, or 0",
+	"Enum.HashOf":             "stable 32-bit hash of a value (FNV-1a of its uuid, or of its string)",
+	"Enum.LookupEntries":      "strings accepted by Parse, in lower case",
+	"Enum.ProtoJSONName":      "protobuf JSON name of a value",
+	"Enum.ValueList":          "comma separated list of the original values",
+	"Enum.VarPrefix":          "prefix of the unexported variables generated for the enum (e.g. authtype, or _enumAuthType)",
+	"Enum.Prefixed":           "name of a function made of a prefix and the enum name, unexported for unexported enums",
+
+	"Value.Original": "value as declared, which is the string representation",
+	"Value.GoName":   "sanitized identifier, to be title cased and prefixed by the enum name",
+	"Value.Doc":      "documentation following a #, if any",
+	"Value.Attrs":    "attributes declared in brackets after the value (uuid, code, weight, topic, event, ce-type, di)",
+
+	"Mapping.From":  "source enum",
+	"Mapping.To":    "target enum",
+	"Mapping.Pairs": "corresponding values",
+
+	"MappingPair.From": "value of the source enum",
+	"MappingPair.To":   "value of the target enum",
+
+	"ProtoEnum.Enum":   "generated enum",
+	"ProtoEnum.Proto":  "Go type of the protobuf enum",
+	"ProtoEnum.Values": "Go constants of the protobuf enum, matching Enum.Values",
+
+	"LookupEntry.Key":   "accepted string, in lower case",
+	"LookupEntry.Value": "value it parses to",
+
+	"title":             "strings.Title",
+	"lower":             "strings.ToLower",
+	"goName":            "GoName of a value",
+	"original":          "Original of a value",
+	"snake":             "string in snake_case (HTTPServer is http_server)",
+	"kebab":             "string in kebab-case (HTTPServer is http-server)",
+	"camel":             "string in camelCase, with the common initialisms in upper case (user-id is userID)",
+	"pascal":            "string in PascalCase, with the common initialisms in upper case (user-id is UserID)",
+	"pluralize":         "English plural of the last word of a string, keeping its case (Category is Categories)",
+	"nameTable":         "concatenated values of an enum, used by the int style String",
+	"nameIndex":         "offsets of the values in nameTable",
+	"nameIndexType":     "smallest unsigned integer type holding the nameIndex offsets",
+	"goldenPath":        "path of the golden file of an enum, by name",
+	"quote":             "Go string literal (strconv.Quote)",
+	"jsonQuote":         "Go string literal of the JSON encoding of a string",
+	"fbsType":           "smallest FlatBuffers integer type for the values",
+	"clickhouseType":    "ClickHouse Enum8/Enum16 column type for the values",
+	"esMapping":         "Elasticsearch keyword mapping (JSON) of the fields storing an enum",
+	"fuzzyKey":          "string compared by ParseFuzzy: in lower case, without separators",
+	"wireSize":          "size in bytes (1 or 2) of the fixed-width binary encoding of the values",
+	"packBits":          "bits used by each of the values in the packed encoding",
+	"graphqlName":       "GraphQL enum value name of a value",
+	"graphqlString":     "GraphQL string literal",
+	"deprecated":        "reports whether a doc starts with \"Deprecated:\"",
+	"deprecationReason": "text following \"Deprecated:\" in a doc",
+	"generatedHeader":   "line marking the generated files, with the release of the generator, as a comment starting with the given marker (// or #)",
+}
+
+// Run prints the schema.
+func (c *templateSchemaCmd) Run() error {
+	schema := buildTemplateSchema()
+	if c.Format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(schema)
+	}
+	return writeTemplateSchema(os.Stdout, schema)
+}
+
+// buildTemplateSchema describes the template data from the types themselves,
+// so that it can't miss a field.
+func buildTemplateSchema() templateSchema {
+	var schema templateSchema
+	for _, t := range schemaTypeNames {
+		st := schemaType{Name: t.name, Doc: t.doc}
+		for i := 0; i < t.typ.NumField(); i++ {
+			f := t.typ.Field(i)
+			st.Fields = append(st.Fields, schemaEntry{f.Name, schemaTypeName(f.Type), templateDocs[t.name+"."+f.Name]})
+		}
+		for i := 0; i < t.typ.NumMethod(); i++ {
+			m := t.typ.Method(i)
+			st.Methods = append(st.Methods, schemaEntry{m.Name, schemaFuncType(m.Type, 1), templateDocs[t.name+"."+m.Name]})
+		}
+		schema.Types = append(schema.Types, st)
+	}
+
+	for name, fn := range templateFuncs {
+		schema.Funcs = append(schema.Funcs, schemaEntry{name, schemaFuncType(reflect.TypeOf(fn), 0), templateDocs[name]})
+	}
+	sort.Slice(schema.Funcs, func(i, j int) bool { return schema.Funcs[i].Name < schema.Funcs[j].Name })
+	return schema
+}
+
+// schemaTypeName returns the name of t, using the documented names of the
+// template data types.
+func schemaTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice:
+		return "[]" + schemaTypeName(t.Elem())
+	case reflect.Ptr:
+		return "*" + schemaTypeName(t.Elem())
+	case reflect.Struct:
+		for _, n := range schemaTypeNames {
+			if n.typ == t {
+				return n.name
+			}
+		}
+	}
+	return t.String()
+}
+
+// schemaFuncType returns the signature of the function type t, skipping the
+// first skip parameters (the receiver of methods).
+func schemaFuncType(t reflect.Type, skip int) string {
+	s := "func("
+	for i := skip; i < t.NumIn(); i++ {
+		if i > skip {
+			s += ", "
+		}
+		s += schemaTypeName(t.In(i))
+	}
+	s += ")"
+	switch t.NumOut() {
+	case 0:
+	case 1:
+		s += " " + schemaTypeName(t.Out(0))
+	default:
+		s += " (" + schemaTypeName(t.Out(0))
+		for i := 1; i < t.NumOut(); i++ {
+			s += ", " + schemaTypeName(t.Out(i))
+		}
+		s += ")"
+	}
+	return s
+}
+
+// writeTemplateSchema writes schema to w in a godoc-like text format.
+func writeTemplateSchema(w io.Writer, schema templateSchema) error {
+	for _, t := range schema.Types {
+		fmt.Fprintf(w, "type %s struct // %s\n", t.Name, t.Doc)
+		for _, f := range t.Fields {
+			fmt.Fprintf(w, "\t.%-14s %-12s %s\n", f.Name, f.Type, f.Doc)
+		}
+		for _, m := range t.Methods {
+			fmt.Fprintf(w, "\t.%s %s\n\t\t%s\n", m.Name, m.Type, m.Doc)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "funcs:")
+	for _, f := range schema.Funcs {
+		if _, err := fmt.Fprintf(w, "\t%-18s %s\n\t\t%s\n", f.Name, f.Type, f.Doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// testOnlyOutput returns the test file the test-only enums of output are
+// generated into.
+func testOnlyOutput(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_test.go"
+}
+
+// hasTestOnly reports whether some enums of def are test-only.
+func hasTestOnly(def fileDef) bool {
+	for _, enum := range def.Enums {
+		if enum.TestOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTestOnly splits def into the definitions generated into the output
+// file and into its test file: the test-only enums, with the mappings and
+// protobuf conversions using them. Other enums can't extend or restrict the
+// test-only ones, which aren't compiled in the package.
+func splitTestOnly(def fileDef) (prod, test fileDef, err error) {
+	prod = fileDef{Package: def.Package}
+	test = fileDef{Package: def.Package}
+	for _, enum := range def.Enums {
+		if enum.TestOnly {
+			test.Enums = append(test.Enums, enum)
+			continue
+		}
+		for _, base := range []*enumDef{enum.Extends, enum.SubsetOf} {
+			if base != nil && base.TestOnly {
+				return prod, test, fmt.Errorf("%s can't be based on the test-only enum %s", enum.Name, base.Name)
+			}
+		}
+		prod.Enums = append(prod.Enums, enum)
+	}
+	for _, m := range def.Mappings {
+		if m.From.TestOnly || m.To.TestOnly {
+			test.Mappings = append(test.Mappings, m)
+		} else {
+			prod.Mappings = append(prod.Mappings, m)
+		}
+	}
+	for _, p := range def.Protos {
+		if p.Enum.TestOnly {
+			test.Protos = append(test.Protos, p)
+		} else {
+			prod.Protos = append(prod.Protos, p)
+		}
+	}
+	return prod, test, nil
+}
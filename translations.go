@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// langRegex matches the BCP 47 language tags accepted in translation files.
+var langRegex = regexp.MustCompile(`^[A-Za-z]{2,8}([-_][A-Za-z0-9]{1,8})*$`)
+
+// translations are the display names of the values of the enums, by enum
+// name, language and value, as read from a translations file:
+//
+//	{"Color": {"it": {"red": "rosso", "green": "verde"}}}
+type translations map[string]map[string]map[string]string
+
+// normalizeLang returns the form of a language tag used as key of the
+// generated tables: lower case, with dashes.
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+}
+
+// applyTranslations sets the labels of the enums of def from the translations
+// file. The translations of the enums not declared in def are ignored, since
+// the file can be shared by several input files.
+func applyTranslations(def fileDef, filename string) (fileDef, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return def, err
+	}
+	var all translations
+	if err := json.Unmarshal(data, &all); err != nil {
+		return def, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+
+	for i := range def.Enums {
+		enum := &def.Enums[i]
+		byLang, ok := all[enum.Name]
+		if !ok || enum.Minimal {
+			continue
+		}
+		langs := make([]string, 0, len(byLang))
+		for lang := range byLang {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+
+		labels := map[string]map[string]string{}
+		for _, lang := range langs {
+			byValue := byLang[lang]
+			if !langRegex.MatchString(lang) {
+				return def, fmt.Errorf("%s: invalid language %q", enum.Name, lang)
+			}
+			lang = normalizeLang(lang)
+			if labels[lang] != nil {
+				return def, fmt.Errorf("%s: language %q is translated twice", enum.Name, lang)
+			}
+			for value := range byValue {
+				if !enum.hasValue(value) {
+					return def, fmt.Errorf("%s: unknown value %q in the %q translations", enum.Name, value, lang)
+				}
+			}
+			labels[lang] = map[string]string{}
+			seen := map[string]string{}
+			for _, v := range enum.Values {
+				value := v.Original
+				label, ok := byValue[value]
+				if !ok {
+					continue
+				}
+				label = strings.TrimSpace(label)
+				if label == "" {
+					return def, fmt.Errorf("%s: empty %q label for %q", enum.Name, lang, value)
+				}
+				if other, ok := seen[strings.ToLower(label)]; ok {
+					return def, fmt.Errorf("%s: values %q and %q have the same %q label %q", enum.Name, other, value, lang, label)
+				}
+				seen[strings.ToLower(label)] = value
+				labels[lang][value] = label
+			}
+		}
+		enum.Labels = labels
+	}
+	return def, nil
+}
+
+// hasValue reports whether the enum declares value.
+func (e enumDef) hasValue(value string) bool {
+	for _, v := range e.Values {
+		if v.Original == value {
+			return true
+		}
+	}
+	return false
+}
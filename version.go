@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// buildVersion is the release of the generator, set at build time with
+// -ldflags "-X main.buildVersion=v1.2.3". Without it, the version of the module is
+// used, as set by go install and go run with a version suffix.
+var buildVersion = ""
+
+// generatorRelease returns the semantic version of the generator, or "" for
+// development builds, including the ones stamped with a pseudo-version of
+// their commit.
+func generatorRelease() string {
+	if semver.IsValid(buildVersion) {
+		return buildVersion
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok || !semver.IsValid(info.Main.Version) || module.IsPseudoVersion(info.Main.Version) || semver.Build(info.Main.Version) != "" {
+		return ""
+	}
+	return info.Main.Version
+}
+
+// generatedHeader returns the comment marking the Go files generated by
+// generator, with its version for release builds.
+func generatedHeader(generator string) string {
+	return generatedComment("//", generator)
+}
+
+// generatedComment returns the line marking the files generated by
+// generator, as a comment starting with marker (// or # depending on the
+// language).
+func generatedComment(marker, generator string) string {
+	if release := generatorRelease(); release != "" {
+		return marker + " Code generated by " + generator + " " + release + ". DO NOT EDIT."
+	}
+	return marker + " Code generated by " + generator + ". DO NOT EDIT."
+}
+
+// templateHeader returns the line marking the files rendered from the
+// templates, as a comment starting with marker.
+func templateHeader(marker string) string {
+	return generatedComment(marker, "go-safe-enum-generator")
+}
+
+// versionString returns the version printed by --version.
+func versionString() string {
+	if release := generatorRelease(); release != "" {
+		return release
+	}
+	return "devel"
+}
+
+// checkRequiredVersion fails if the generator is older than required, as
+// declared by the require-version directive option. Development builds meet
+// any requirement.
+func checkRequiredVersion(required string) error {
+	if !semver.IsValid(required) {
+		return fmt.Errorf("invalid required version %q (must be like v1.2.3)", required)
+	}
+	release := generatorRelease()
+	if release == "" {
+		logger.Debug("development build, not checking the required version", "required", required)
+		return nil
+	}
+	if semver.Compare(release, required) < 0 {
+		return fmt.Errorf("requires go-safe-enum-generator %s, but this is %s (update it, or run it with go run github.com/panta/go-safe-enum-generator@%s)", required, release, required)
+	}
+	return nil
+}
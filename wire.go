@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// wireSize returns the size in bytes of the fixed-width binary encoding of
+// values: their int mapping plus one, 0 standing for the zero value.
+func wireSize(values []valueInfo) (int, error) {
+	switch {
+	case len(values) <= math.MaxUint8:
+		return 1, nil
+	case len(values) <= math.MaxUint16:
+		return 2, nil
+	}
+	return 0, fmt.Errorf("too many values for a fixed-width encoding (%d)", len(values))
+}
+
+// packBits returns the number of bits used to pack each of values by their
+// int mapping: ceil(log2(n)), at least one.
+func packBits(values []valueInfo) int {
+	if len(values) <= 2 {
+		return 1
+	}
+	return bits.Len(uint(len(values) - 1))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+const zeroStringInput = `package p
+
+// ENUM Color (red, green, blue)
+
+// ENUM Shape (circle, square) style=const
+`
+
+const zeroStringCheck = `package p
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type zeroEnum interface {
+	String() string
+	MarshalText() ([]byte, error)
+}
+
+func checkZero(t *testing.T, zero zeroEnum, wantString string, decode func([]byte) (interface{}, error)) {
+	t.Helper()
+	if got := zero.String(); got != wantString {
+		t.Errorf("String() = %q, want %q", got, wantString)
+	}
+	text, err := zero.MarshalText()
+	if err != nil || string(text) != "" {
+		t.Errorf("MarshalText() = %q, %v, want an empty string", text, err)
+	}
+	data, err := json.Marshal(zero)
+	if err != nil || string(data) != ` + "`" + `""` + "`" + ` {
+		t.Errorf("json.Marshal = %s, %v, want an empty string", data, err)
+	}
+	if got, _ := decode(data); got != zero {
+		t.Errorf("JSON round trip of the zero value through %s = %v", data, got)
+	}
+}
+
+func TestZeroRoundTrip(t *testing.T) {
+	var color Color
+	checkZero(t, color, wantColor, func(data []byte) (interface{}, error) {
+		var v Color
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	if v, err := color.Value(); err != nil || v != "" {
+		t.Errorf("Value() = %q, %v, want an empty string", v, err)
+	}
+	var shape Shape
+	checkZero(t, shape, wantShape, func(data []byte) (interface{}, error) {
+		var v Shape
+		err := json.Unmarshal(data, &v)
+		return v, err
+	})
+	if v, err := shape.Value(); err != nil || v != "" {
+		t.Errorf("Value() = %q, %v, want an empty string", v, err)
+	}
+}
+`
+
+// TestZeroStringRoundTrip generates enums with each zero-string mode and
+// checks that the mode only changes String: the zero value is still encoded
+// as an empty string, and decoded back to the zero value.
+func TestZeroStringRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds generated code")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not available")
+	}
+	tests := []struct {
+		mode         string
+		color, shape string
+	}{
+		{"empty", "", ""},
+		{"invalid", "<invalid Color>", "<invalid Shape>"},
+		{"default", "red", "circle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			dir := t.TempDir()
+			write := func(name, content string) {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			write("go.mod", "module p\n\ngo 1.22\n")
+			write("enums.go", zeroStringInput)
+			write("zero_test.go", zeroStringCheck+"\nconst wantColor, wantShape = "+
+				`"`+tt.color+`", "`+tt.shape+`"`+"\n")
+
+			opts := defaultGenOptions()
+			opts.ZeroString = tt.mode
+			if err := processFile(filepath.Join(dir, "enums.go"), filepath.Join(dir, "enums_gen.go"), opts); err != nil {
+				t.Fatal(err)
+			}
+			cmd := exec.Command("go", "test", "./...")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go test: %v\n%s", err, out)
+			}
+		})
+	}
+}